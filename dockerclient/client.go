@@ -0,0 +1,196 @@
+// Package dockerclient is a client of the Docker Engine API with minimal dependencies,
+// built on the configuration resolved by the dockercontext and dockerconfig packages.
+package dockerclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/mdelapenya/docker-sdk-go/dockercontext"
+)
+
+// userAgent is the User-Agent header of the requests, unless set in the HTTP headers of config.json
+const userAgent = "docker-sdk-go"
+
+// Client is a client of the Docker Engine API. It's safe for concurrent use.
+type Client struct {
+	// cfg is the configuration the client was created with
+	cfg dockercontext.ClientConfig
+
+	// httpClient sends the requests to the Docker daemon
+	httpClient *http.Client
+
+	// baseURL is the URL of the Docker daemon, without the API version
+	baseURL *url.URL
+
+	// mu guards the fields below
+	mu sync.Mutex
+
+	// version is the API version used in the requests
+	version string
+
+	// negotiated reports whether the version is pinned or was negotiated
+	negotiated bool
+
+	// negotiating is closed once the ping negotiating the version in flight, if any, returns
+	negotiating chan struct{}
+}
+
+// New returns a client of the Docker daemon of the given configuration.
+//
+// If the configuration pins an API version, it's used in every request. Otherwise, the
+// API version is negotiated with the Docker daemon on the first request, see [Client.NegotiateAPIVersion].
+func New(cfg dockercontext.ClientConfig) (*Client, error) {
+	httpClient, err := cfg.HTTPClient()
+	if err != nil {
+		return nil, fmt.Errorf("http client: %w", err)
+	}
+
+	baseURL, err := cfg.Endpoint.URL()
+	if err != nil {
+		return nil, fmt.Errorf("base url: %w", err)
+	}
+
+	return &Client{
+		cfg:        cfg,
+		httpClient: httpClient,
+		baseURL:    baseURL,
+		version:    cfg.APIVersion,
+		negotiated: cfg.APIVersion != "",
+	}, nil
+}
+
+// NewFromEnv returns a client of the Docker daemon of the current context, with the
+// configuration resolved from the environment and config.json by [dockercontext.Resolve].
+func NewFromEnv() (*Client, error) {
+	cfg, err := dockercontext.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("resolve client config: %w", err)
+	}
+
+	return New(cfg)
+}
+
+// Host returns the Docker host the client connects to.
+func (c *Client) Host() string {
+	return c.cfg.Endpoint.Host
+}
+
+// Close releases the idle connections to the Docker daemon.
+func (c *Client) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// request is a request to the Docker Engine API
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   io.Reader
+
+	// unversioned sends the request without the API version prefix, e.g. for "/_ping"
+	unversioned bool
+}
+
+// get sends a GET request to the given path of the Docker Engine API.
+func (c *Client) get(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	return c.send(ctx, request{method: http.MethodGet, path: path, query: query})
+}
+
+// post sends a POST request to the given path of the Docker Engine API,
+// with the given value as JSON body, if not nil.
+func (c *Client) post(ctx context.Context, path string, query url.Values, v any) (*http.Response, error) {
+	req := request{method: http.MethodPost, path: path, query: query}
+	if v != nil {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+		req.body = bytes.NewReader(data)
+		req.header = http.Header{"Content-Type": {"application/json"}}
+	}
+
+	return c.send(ctx, req)
+}
+
+// delete sends a DELETE request to the given path of the Docker Engine API.
+func (c *Client) delete(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	return c.send(ctx, request{method: http.MethodDelete, path: path, query: query})
+}
+
+// send sends the request to the Docker daemon, negotiating the API version first if needed.
+// It returns an [*APIError] if the response has an error status, or the response otherwise,
+// whose body must be closed by the caller.
+func (c *Client) send(ctx context.Context, r request) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if !r.unversioned {
+		version, err := c.negotiatedVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		u.Path += "/v" + version
+	}
+	u.Path += r.path
+	u.RawQuery = r.query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, r.method, u.String(), r.body)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, c.connectionError(ctx, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest || resp.StatusCode == http.StatusNotModified {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+
+	return resp, nil
+}
+
+// connectionError wraps the error sending a request, reporting the cancellation of
+// the context as is, and any other error as [ErrConnectionFailed].
+func (c *Client) connectionError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("send request: %w", ctxErr)
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	return fmt.Errorf("%w at %s, is the docker daemon running? %w", ErrConnectionFailed, c.Host(), err)
+}
+
+// decodeJSON decodes the JSON body of the response into v, closing it.
+func decodeJSON(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
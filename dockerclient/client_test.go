@@ -0,0 +1,199 @@
+package dockerclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdelapenya/docker-sdk-go/dockercontext"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("invalid-host", func(tt *testing.T) {
+		_, err := New(dockercontext.ClientConfig{Endpoint: dockercontext.DockerEndpoint{Host: "tpc://127.0.0.1:2375"}})
+		require.ErrorContains(tt, err, `unsupported protocol "tpc"`)
+	})
+
+	t.Run("host", func(tt *testing.T) {
+		cli, err := New(dockercontext.ClientConfig{Endpoint: dockercontext.DockerEndpoint{Host: "tcp://127.0.0.1:2375"}})
+		require.NoError(tt, err)
+		require.Equal(tt, "tcp://127.0.0.1:2375", cli.Host())
+		require.NoError(tt, cli.Close())
+	})
+}
+
+func TestNewFromEnv(t *testing.T) {
+	mux := newDaemonMux(t, "1.45")
+	mux.HandleFunc("GET /v1.45/version", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "tests", r.Header.Get("X-Meta-Source"))
+		_, _ = io.WriteString(w, `{"Version":"26.1.0","ApiVersion":"1.45"}`)
+	})
+	ep := serveDaemon(t, mux)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home) // Windows support
+	t.Setenv("DOCKER_CONFIG", "")
+	t.Setenv("DOCKER_AUTH_CONFIG", `{"auths":{},"HttpHeaders":{"X-Meta-Source":"tests"}}`)
+	t.Setenv(dockercontext.EnvOverrideHost, ep.Host)
+	t.Setenv(dockercontext.EnvTLSVerify, "")
+	t.Setenv(dockercontext.EnvOverrideAPIVersion, "")
+
+	cli, err := NewFromEnv()
+	require.NoError(t, err)
+
+	v, err := cli.ServerVersion(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "26.1.0", v.Version)
+
+	t.Run("invalid", func(tt *testing.T) {
+		tt.Setenv(dockercontext.EnvOverrideAPIVersion, "latest")
+
+		_, err := NewFromEnv()
+		require.ErrorContains(tt, err, "resolve client config")
+	})
+}
+
+func TestClient_send(t *testing.T) {
+	mux := newDaemonMux(t, DefaultAPIVersion)
+	mux.HandleFunc("GET /v1.47/not-found", errorHandler(http.StatusNotFound, "No such container: foo"))
+	mux.HandleFunc("GET /v1.47/plain-error", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "something failed", http.StatusInternalServerError)
+	})
+	mux.HandleFunc("GET /v1.47/empty-error", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	mux.HandleFunc("GET /v1.47/blocked", func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("GET /v1.47/echo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-User-Agent", r.Header.Get("User-Agent"))
+		_, _ = io.WriteString(w, r.URL.RawQuery)
+	})
+
+	cli := newTestClient(t, serveDaemon(t, mux), "")
+
+	t.Run("success", func(tt *testing.T) {
+		resp, err := cli.get(context.Background(), "/echo", map[string][]string{"all": {"1"}})
+		require.NoError(tt, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(tt, err)
+		require.Equal(tt, "all=1", string(body))
+		require.Equal(tt, userAgent, resp.Header.Get("X-User-Agent"))
+	})
+
+	t.Run("api-error", func(tt *testing.T) {
+		_, err := cli.get(context.Background(), "/not-found", nil)
+		require.ErrorIs(tt, err, ErrNotFound)
+		require.NotErrorIs(tt, err, ErrConflict)
+
+		var apiErr *APIError
+		require.ErrorAs(tt, err, &apiErr)
+		require.Equal(tt, &APIError{StatusCode: http.StatusNotFound, Message: "No such container: foo"}, apiErr)
+		require.EqualError(tt, err, "docker daemon: No such container: foo (status 404)")
+	})
+
+	t.Run("api-error/plain-text", func(tt *testing.T) {
+		_, err := cli.get(context.Background(), "/plain-error", nil)
+		require.ErrorIs(tt, err, ErrSystem)
+		require.EqualError(tt, err, "docker daemon: something failed (status 500)")
+	})
+
+	t.Run("api-error/empty", func(tt *testing.T) {
+		_, err := cli.get(context.Background(), "/empty-error", nil)
+		require.ErrorIs(tt, err, ErrConflict)
+		require.EqualError(tt, err, "docker daemon: Conflict (status 409)")
+	})
+
+	t.Run("canceled", func(tt *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			cancel()
+		}()
+
+		_, err := cli.get(ctx, "/blocked", nil)
+		require.ErrorIs(tt, err, context.Canceled)
+		require.NotErrorIs(tt, err, ErrConnectionFailed)
+	})
+
+	t.Run("connection-failed", func(tt *testing.T) {
+		cli := newTestClient(tt, dockercontext.DockerEndpoint{Host: "unix://" + filepath.Join(tt.TempDir(), "docker.sock")}, "1.47")
+
+		_, err := cli.get(context.Background(), "/echo", nil)
+		require.ErrorIs(tt, err, ErrConnectionFailed)
+		require.ErrorContains(tt, err, "is the docker daemon running?")
+	})
+}
+
+// newDaemonMux returns the handler of a fake Docker daemon, answering the ping
+// requests with the given API version.
+func newDaemonMux(t *testing.T, apiVersion string) *http.ServeMux {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /_ping", func(w http.ResponseWriter, _ *http.Request) {
+		if apiVersion != "" {
+			w.Header().Set("Api-Version", apiVersion)
+		}
+		w.Header().Set("Ostype", "linux")
+		_, _ = io.WriteString(w, "OK")
+	})
+
+	return mux
+}
+
+// serveDaemon serves the fake Docker daemon on a unix socket, or on TCP on Windows,
+// until the test ends. It returns the endpoint to connect to it.
+func serveDaemon(t *testing.T, handler http.Handler) dockercontext.DockerEndpoint {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		return dockercontext.DockerEndpoint{Host: "tcp://" + srv.Listener.Addr().String()}
+	}
+
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	return dockercontext.DockerEndpoint{Host: "unix://" + socket}
+}
+
+// newTestClient returns a client of the given endpoint, with the given pinned API version, if any.
+func newTestClient(t *testing.T, ep dockercontext.DockerEndpoint, apiVersion string) *Client {
+	t.Helper()
+
+	cli, err := New(dockercontext.ClientConfig{Endpoint: ep, APIVersion: apiVersion})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, cli.Close())
+	})
+
+	return cli
+}
+
+// errorHandler returns a handler answering with the given status and error message.
+func errorHandler(status int, msg string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": msg})
+	}
+}
@@ -0,0 +1,250 @@
+package dockerclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// stopSignalVersion is the first API version supporting the signal of the stop and restart requests
+const stopSignalVersion = "1.42"
+
+// StopOptions configures how a container is stopped, or restarted.
+type StopOptions struct {
+	// Signal is the signal sent to stop the container, e.g. "SIGTERM".
+	// It defaults to the stop signal of the container, and requires API version 1.42 or newer.
+	Signal string
+
+	// Timeout is the number of seconds to wait for the container to stop before killing it.
+	// It defaults to the stop timeout of the container, or 10 seconds. A negative value waits forever.
+	Timeout *int
+}
+
+// RemoveOptions configures how a container is removed.
+type RemoveOptions struct {
+	// RemoveVolumes removes the anonymous volumes of the container.
+	RemoveVolumes bool
+
+	// RemoveLinks removes the link of the container, instead of the container itself.
+	RemoveLinks bool
+
+	// Force kills the container if it's running, before removing it.
+	Force bool
+}
+
+// ListOptions configures which containers are listed.
+type ListOptions struct {
+	// All lists all the containers, instead of the running ones only.
+	All bool
+
+	// Limit lists only the given number of the most recently created containers, if positive.
+	Limit int
+
+	// Size reports the size of the containers, in SizeRw and SizeRootFs.
+	Size bool
+
+	// Filters selects the containers to list, e.g. {"status": {"exited"}, "label": {"app=web"}}.
+	Filters Filters
+}
+
+// ContainerCreate creates a container with the given configuration, like "docker create".
+// The host and networking configurations are optional, as are the platform, e.g. "linux/amd64",
+// and the name of the container, which is generated by the Docker daemon if empty.
+func (c *Client) ContainerCreate(ctx context.Context, config *ContainerConfig, hostConfig *HostConfig,
+	networkingConfig *NetworkingConfig, platform string, containerName string,
+) (ContainerCreateResponse, error) {
+	if config == nil {
+		config = &ContainerConfig{}
+	}
+
+	body := struct {
+		*ContainerConfig
+		HostConfig       *HostConfig       `json:"HostConfig,omitempty"`
+		NetworkingConfig *NetworkingConfig `json:"NetworkingConfig,omitempty"`
+	}{
+		ContainerConfig:  config,
+		HostConfig:       hostConfig,
+		NetworkingConfig: networkingConfig,
+	}
+
+	query := url.Values{}
+	if containerName != "" {
+		query.Set("name", containerName)
+	}
+	if platform != "" {
+		query.Set("platform", platform)
+	}
+
+	resp, err := c.post(ctx, "/containers/create", query, body)
+	if err != nil {
+		return ContainerCreateResponse{}, fmt.Errorf("create container: %w", err)
+	}
+
+	var created ContainerCreateResponse
+	if err := decodeJSON(resp, &created); err != nil {
+		return ContainerCreateResponse{}, fmt.Errorf("create container: %w", err)
+	}
+
+	return created, nil
+}
+
+// ContainerStart starts a container, like "docker start".
+// Starting a running container is not an error.
+func (c *Client) ContainerStart(ctx context.Context, containerID string) error {
+	return c.containerAction(ctx, "start", containerID, nil)
+}
+
+// ContainerStop stops a container, like "docker stop".
+// Stopping a stopped container is not an error.
+func (c *Client) ContainerStop(ctx context.Context, containerID string, opts StopOptions) error {
+	query, err := c.stopQuery(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("stop container: %w", err)
+	}
+
+	return c.containerAction(ctx, "stop", containerID, query)
+}
+
+// ContainerRestart restarts a container, like "docker restart".
+func (c *Client) ContainerRestart(ctx context.Context, containerID string, opts StopOptions) error {
+	query, err := c.stopQuery(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("restart container: %w", err)
+	}
+
+	return c.containerAction(ctx, "restart", containerID, query)
+}
+
+// ContainerKill sends a signal to a container, like "docker kill".
+// The signal defaults to "SIGKILL" if empty.
+func (c *Client) ContainerKill(ctx context.Context, containerID string, signal string) error {
+	query := url.Values{}
+	if signal != "" {
+		query.Set("signal", signal)
+	}
+
+	return c.containerAction(ctx, "kill", containerID, query)
+}
+
+// ContainerRemove removes a container, like "docker rm".
+func (c *Client) ContainerRemove(ctx context.Context, containerID string, opts RemoveOptions) error {
+	if err := checkContainerID(containerID); err != nil {
+		return fmt.Errorf("remove container: %w", err)
+	}
+
+	query := url.Values{}
+	if opts.RemoveVolumes {
+		query.Set("v", "1")
+	}
+	if opts.RemoveLinks {
+		query.Set("link", "1")
+	}
+	if opts.Force {
+		query.Set("force", "1")
+	}
+
+	resp, err := c.delete(ctx, "/containers/"+containerID, query)
+	if err != nil {
+		return fmt.Errorf("remove container: %w", err)
+	}
+
+	return resp.Body.Close()
+}
+
+// ContainerInspect returns the low-level information of a container, like "docker inspect".
+func (c *Client) ContainerInspect(ctx context.Context, containerID string) (ContainerJSON, error) {
+	if err := checkContainerID(containerID); err != nil {
+		return ContainerJSON{}, fmt.Errorf("inspect container: %w", err)
+	}
+
+	resp, err := c.get(ctx, "/containers/"+containerID+"/json", nil)
+	if err != nil {
+		return ContainerJSON{}, fmt.Errorf("inspect container: %w", err)
+	}
+
+	var container ContainerJSON
+	if err := decodeJSON(resp, &container); err != nil {
+		return ContainerJSON{}, fmt.Errorf("inspect container: %w", err)
+	}
+
+	return container, nil
+}
+
+// ContainerList lists the containers, like "docker ps".
+func (c *Client) ContainerList(ctx context.Context, opts ListOptions) ([]Container, error) {
+	query := url.Values{}
+	if opts.All {
+		query.Set("all", "1")
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Size {
+		query.Set("size", "1")
+	}
+	if err := opts.Filters.setQuery(query); err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+
+	resp, err := c.get(ctx, "/containers/json", query)
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+
+	var containers []Container
+	if err := decodeJSON(resp, &containers); err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+
+	return containers, nil
+}
+
+// containerAction sends a POST request to the given action of a container, e.g. "start".
+// A 304 Not Modified response, meaning the container is already in the requested state, is not an error.
+func (c *Client) containerAction(ctx context.Context, action, containerID string, query url.Values) error {
+	if err := checkContainerID(containerID); err != nil {
+		return fmt.Errorf("%s container: %w", action, err)
+	}
+
+	resp, err := c.post(ctx, "/containers/"+containerID+"/"+action, query, nil)
+	if err != nil {
+		if errors.Is(err, ErrNotModified) {
+			return nil
+		}
+		return fmt.Errorf("%s container: %w", action, err)
+	}
+
+	return resp.Body.Close()
+}
+
+// stopQuery returns the query of the stop and restart requests. The signal is only
+// sent to Docker daemons supporting it, so the API version is negotiated first.
+func (c *Client) stopQuery(ctx context.Context, opts StopOptions) (url.Values, error) {
+	query := url.Values{}
+	if opts.Timeout != nil {
+		query.Set("t", strconv.Itoa(*opts.Timeout))
+	}
+
+	if opts.Signal != "" {
+		version, err := c.negotiatedVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if compareVersions(version, stopSignalVersion) >= 0 {
+			query.Set("signal", opts.Signal)
+		}
+	}
+
+	return query, nil
+}
+
+// checkContainerID checks that the container ID, or name, is not empty, as it would
+// send the request to a different endpoint.
+func checkContainerID(containerID string) error {
+	if containerID == "" {
+		return fmt.Errorf("%w: container ID is empty", ErrInvalidParameter)
+	}
+	return nil
+}
@@ -0,0 +1,406 @@
+package dockerclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ContainerLifecycle(t *testing.T) {
+	daemon, cli := newFakeDaemon(t, DefaultAPIVersion)
+	ctx := context.Background()
+
+	created, err := cli.ContainerCreate(ctx,
+		&ContainerConfig{
+			Image:        "nginx:alpine",
+			Env:          []string{"FOO=bar"},
+			ExposedPorts: map[string]struct{}{"80/tcp": {}},
+			Labels:       map[string]string{"app": "web"},
+		},
+		&HostConfig{
+			PortBindings:  map[string][]PortBinding{"80/tcp": {{HostIP: "127.0.0.1", HostPort: "8080"}}},
+			RestartPolicy: RestartPolicy{Name: "on-failure", MaximumRetryCount: 3},
+		},
+		&NetworkingConfig{
+			EndpointsConfig: map[string]*EndpointSettings{"backend": {Aliases: []string{"web"}}},
+		},
+		"linux/amd64", "web",
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	require.Equal(t, url.Values{"name": {"web"}, "platform": {"linux/amd64"}}, daemon.lastQuery("create"))
+
+	t.Run("inspect/created", func(tt *testing.T) {
+		container, err := cli.ContainerInspect(ctx, "web")
+		require.NoError(tt, err)
+		require.Equal(tt, created.ID, container.ID)
+		require.Equal(tt, "/web", container.Name)
+		require.Equal(tt, "created", container.State.Status)
+		require.Equal(tt, "nginx:alpine", container.Config.Image)
+		require.Equal(tt, []string{"FOO=bar"}, container.Config.Env)
+		require.Equal(tt, RestartPolicy{Name: "on-failure", MaximumRetryCount: 3}, container.HostConfig.RestartPolicy)
+		require.Equal(tt, []PortBinding{{HostIP: "127.0.0.1", HostPort: "8080"}}, container.HostConfig.PortBindings["80/tcp"])
+		require.Equal(tt, []string{"web"}, container.NetworkSettings.Networks["backend"].Aliases)
+	})
+
+	t.Run("start", func(tt *testing.T) {
+		require.NoError(tt, cli.ContainerStart(ctx, created.ID))
+		require.NoError(tt, cli.ContainerStart(ctx, created.ID)) // already started
+
+		container, err := cli.ContainerInspect(ctx, created.ID)
+		require.NoError(tt, err)
+		require.True(tt, container.State.Running)
+	})
+
+	t.Run("restart", func(tt *testing.T) {
+		timeout := 5
+		require.NoError(tt, cli.ContainerRestart(ctx, "web", StopOptions{Signal: "SIGINT", Timeout: &timeout}))
+		require.Equal(tt, url.Values{"signal": {"SIGINT"}, "t": {"5"}}, daemon.lastQuery("restart"))
+
+		container, err := cli.ContainerInspect(ctx, "web")
+		require.NoError(tt, err)
+		require.True(tt, container.State.Running)
+		require.Equal(tt, 1, container.RestartCount)
+	})
+
+	t.Run("list", func(tt *testing.T) {
+		other, err := cli.ContainerCreate(ctx, &ContainerConfig{Image: "redis", Labels: map[string]string{"app": "cache"}}, nil, nil, "", "")
+		require.NoError(tt, err)
+
+		containers, err := cli.ContainerList(ctx, ListOptions{})
+		require.NoError(tt, err)
+		require.Equal(tt, []string{created.ID}, containerIDs(containers))
+		require.Equal(tt, []string{"/web"}, containers[0].Names)
+		require.Equal(tt, "running", containers[0].State)
+
+		containers, err = cli.ContainerList(ctx, ListOptions{All: true})
+		require.NoError(tt, err)
+		require.ElementsMatch(tt, []string{created.ID, other.ID}, containerIDs(containers))
+
+		filters := Filters{}
+		filters.Add("label", "app=cache")
+		containers, err = cli.ContainerList(ctx, ListOptions{All: true, Limit: 10, Size: true, Filters: filters})
+		require.NoError(tt, err)
+		require.Equal(tt, []string{other.ID}, containerIDs(containers))
+		require.Equal(tt, url.Values{
+			"all":     {"1"},
+			"limit":   {"10"},
+			"size":    {"1"},
+			"filters": {`{"label":{"app=cache":true}}`},
+		}, daemon.lastQuery("list"))
+
+		require.NoError(tt, cli.ContainerRemove(ctx, other.ID, RemoveOptions{}))
+	})
+
+	t.Run("remove/running", func(tt *testing.T) {
+		err := cli.ContainerRemove(ctx, "web", RemoveOptions{})
+		require.ErrorIs(tt, err, ErrConflict)
+		require.ErrorContains(tt, err, "remove container: docker daemon: cannot remove container")
+	})
+
+	t.Run("kill", func(tt *testing.T) {
+		require.NoError(tt, cli.ContainerKill(ctx, "web", "SIGTERM"))
+		require.Equal(tt, url.Values{"signal": {"SIGTERM"}}, daemon.lastQuery("kill"))
+
+		container, err := cli.ContainerInspect(ctx, "web")
+		require.NoError(tt, err)
+		require.Equal(tt, "exited", container.State.Status)
+
+		err = cli.ContainerKill(ctx, "web", "")
+		require.ErrorIs(tt, err, ErrConflict)
+	})
+
+	t.Run("stop", func(tt *testing.T) {
+		require.NoError(tt, cli.ContainerStart(ctx, "web"))
+		require.NoError(tt, cli.ContainerStop(ctx, "web", StopOptions{}))
+		require.Empty(tt, daemon.lastQuery("stop"))
+		require.NoError(tt, cli.ContainerStop(ctx, "web", StopOptions{})) // already stopped
+
+		container, err := cli.ContainerInspect(ctx, "web")
+		require.NoError(tt, err)
+		require.False(tt, container.State.Running)
+	})
+
+	t.Run("remove", func(tt *testing.T) {
+		require.NoError(tt, cli.ContainerStart(ctx, "web"))
+		require.NoError(tt, cli.ContainerRemove(ctx, "web", RemoveOptions{RemoveVolumes: true, Force: true}))
+		require.Equal(tt, url.Values{"v": {"1"}, "force": {"1"}}, daemon.lastQuery("remove"))
+
+		_, err := cli.ContainerInspect(ctx, "web")
+		require.ErrorIs(tt, err, ErrNotFound)
+		require.ErrorContains(tt, err, "No such container: web")
+	})
+}
+
+func TestClient_ContainerCreate(t *testing.T) {
+	t.Run("name-conflict", func(tt *testing.T) {
+		_, cli := newFakeDaemon(tt, DefaultAPIVersion)
+
+		_, err := cli.ContainerCreate(context.Background(), &ContainerConfig{Image: "nginx"}, nil, nil, "", "web")
+		require.NoError(tt, err)
+
+		_, err = cli.ContainerCreate(context.Background(), &ContainerConfig{Image: "nginx"}, nil, nil, "", "web")
+		require.ErrorIs(tt, err, ErrConflict)
+	})
+
+	t.Run("no-such-image", func(tt *testing.T) {
+		_, cli := newFakeDaemon(tt, DefaultAPIVersion)
+
+		_, err := cli.ContainerCreate(context.Background(), &ContainerConfig{Image: "missing"}, nil, nil, "", "")
+		require.ErrorIs(tt, err, ErrNotFound)
+	})
+}
+
+func TestClient_ContainerStop(t *testing.T) {
+	t.Run("signal/unsupported", func(tt *testing.T) {
+		daemon, cli := newFakeDaemon(tt, "1.41")
+		ctx := context.Background()
+
+		created, err := cli.ContainerCreate(ctx, &ContainerConfig{Image: "nginx"}, nil, nil, "", "")
+		require.NoError(tt, err)
+		require.NoError(tt, cli.ContainerStart(ctx, created.ID))
+
+		timeout := -1
+		require.NoError(tt, cli.ContainerStop(ctx, created.ID, StopOptions{Signal: "SIGINT", Timeout: &timeout}))
+		require.Equal(tt, url.Values{"t": {"-1"}}, daemon.lastQuery("stop")) // no signal before 1.42
+	})
+
+	t.Run("empty-id", func(tt *testing.T) {
+		_, cli := newFakeDaemon(tt, DefaultAPIVersion)
+
+		err := cli.ContainerStop(context.Background(), "", StopOptions{})
+		require.ErrorIs(tt, err, ErrInvalidParameter)
+	})
+}
+
+// fakeDaemon is a fake Docker daemon, keeping the containers in memory
+type fakeDaemon struct {
+	t *testing.T
+
+	mu         sync.Mutex
+	containers map[string]*ContainerJSON
+	created    []string
+	queries    map[string]url.Values
+}
+
+// newFakeDaemon starts a fake Docker daemon with the given API version, serving the container
+// endpoints over a unix socket. It returns the daemon, and a client negotiating the API version.
+func newFakeDaemon(t *testing.T, apiVersion string) (*fakeDaemon, *Client) {
+	t.Helper()
+
+	d := &fakeDaemon{t: t, containers: map[string]*ContainerJSON{}, queries: map[string]url.Values{}}
+
+	mux := newDaemonMux(t, apiVersion)
+	prefix := "/v" + apiVersion
+	mux.HandleFunc("POST "+prefix+"/containers/create", d.create)
+	mux.HandleFunc("POST "+prefix+"/containers/{id}/{action}", d.action)
+	mux.HandleFunc("DELETE "+prefix+"/containers/{id}", d.remove)
+	mux.HandleFunc("GET "+prefix+"/containers/{id}/json", d.inspect)
+	mux.HandleFunc("GET "+prefix+"/containers/json", d.list)
+
+	return d, newTestClient(t, serveDaemon(t, mux), "")
+}
+
+// lastQuery returns the query of the last request of the given kind, e.g. "create".
+func (d *fakeDaemon) lastQuery(kind string) url.Values {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.queries[kind]
+}
+
+// find returns the container with the given ID or name. It must be called with the lock held.
+func (d *fakeDaemon) find(idOrName string) *ContainerJSON {
+	if c, ok := d.containers[idOrName]; ok {
+		return c
+	}
+	for _, c := range d.containers {
+		if c.Name == "/"+idOrName {
+			return c
+		}
+	}
+	return nil
+}
+
+func (d *fakeDaemon) create(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ContainerConfig
+		HostConfig       *HostConfig
+		NetworkingConfig *NetworkingConfig
+	}
+	require.NoError(d.t, json.NewDecoder(r.Body).Decode(&body))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries["create"] = r.URL.Query()
+
+	if body.Image == "missing" {
+		errorHandler(http.StatusNotFound, "No such image: "+body.Image)(w, r)
+		return
+	}
+
+	id := fmt.Sprintf("%064x", len(d.created)+1)
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = "container-" + id[60:]
+	}
+	if d.find(name) != nil {
+		errorHandler(http.StatusConflict, "Conflict. The container name \"/"+name+"\" is already in use")(w, r)
+		return
+	}
+
+	networks := map[string]*EndpointSettings{}
+	if body.NetworkingConfig != nil {
+		networks = body.NetworkingConfig.EndpointsConfig
+	}
+
+	d.created = append(d.created, id)
+	d.containers[id] = &ContainerJSON{
+		ID:              id,
+		Name:            "/" + name,
+		Image:           body.Image,
+		State:           &ContainerState{Status: "created"},
+		Config:          &body.ContainerConfig,
+		HostConfig:      body.HostConfig,
+		NetworkSettings: &NetworkSettings{Networks: networks},
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	require.NoError(d.t, json.NewEncoder(w).Encode(ContainerCreateResponse{ID: id, Warnings: []string{}}))
+}
+
+func (d *fakeDaemon) action(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	action := r.PathValue("action")
+	d.queries[action] = r.URL.Query()
+
+	c := d.find(r.PathValue("id"))
+	if c == nil {
+		errorHandler(http.StatusNotFound, "No such container: "+r.PathValue("id"))(w, r)
+		return
+	}
+
+	switch action {
+	case "start":
+		if c.State.Running {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		c.State.Running, c.State.Status = true, "running"
+	case "stop":
+		if !c.State.Running {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		c.State.Running, c.State.Status = false, "exited"
+	case "restart":
+		c.State.Running, c.State.Status = true, "running"
+		c.RestartCount++
+	case "kill":
+		if !c.State.Running {
+			errorHandler(http.StatusConflict, "Cannot kill container: "+c.ID+" is not running")(w, r)
+			return
+		}
+		c.State.Running, c.State.Status = false, "exited"
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (d *fakeDaemon) remove(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries["remove"] = r.URL.Query()
+
+	c := d.find(r.PathValue("id"))
+	if c == nil {
+		errorHandler(http.StatusNotFound, "No such container: "+r.PathValue("id"))(w, r)
+		return
+	}
+
+	if c.State.Running && r.URL.Query().Get("force") != "1" {
+		errorHandler(http.StatusConflict, "cannot remove container \""+c.Name+"\": container is running: stop the container before removing or force remove")(w, r)
+		return
+	}
+
+	delete(d.containers, c.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (d *fakeDaemon) inspect(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c := d.find(r.PathValue("id"))
+	if c == nil {
+		errorHandler(http.StatusNotFound, "No such container: "+r.PathValue("id"))(w, r)
+		return
+	}
+
+	require.NoError(d.t, json.NewEncoder(w).Encode(c))
+}
+
+func (d *fakeDaemon) list(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries["list"] = r.URL.Query()
+
+	var filters map[string]map[string]bool
+	if f := r.URL.Query().Get("filters"); f != "" {
+		require.NoError(d.t, json.Unmarshal([]byte(f), &filters))
+	}
+
+	containers := []Container{}
+	for _, id := range d.created {
+		c, ok := d.containers[id]
+		if !ok || (!c.State.Running && r.URL.Query().Get("all") != "1") {
+			continue
+		}
+
+		if labels := filters["label"]; len(labels) > 0 {
+			matches := false
+			for label := range labels {
+				key, value, _ := strings.Cut(label, "=")
+				if c.Config.Labels[key] == value {
+					matches = true
+				}
+			}
+			if !matches {
+				continue
+			}
+		}
+
+		containers = append(containers, Container{
+			ID:     c.ID,
+			Names:  []string{c.Name},
+			Image:  c.Image,
+			Labels: c.Config.Labels,
+			State:  c.State.Status,
+		})
+	}
+
+	require.NoError(d.t, json.NewEncoder(w).Encode(containers))
+}
+
+// containerIDs returns the sorted IDs of the given containers.
+func containerIDs(containers []Container) []string {
+	ids := make([]string, 0, len(containers))
+	for _, c := range containers {
+		ids = append(ids, c.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,191 @@
+package dockerclient
+
+// The types in this file model the container objects of the Docker Engine API,
+// with the same JSON names as https://docs.docker.com/reference/api/engine/version/v1.47/
+// Only the most commonly used fields are included.
+
+// ContainerConfig is the configuration of a container that doesn't depend on the host.
+type ContainerConfig struct {
+	Hostname     string              `json:"Hostname,omitempty"`
+	Domainname   string              `json:"Domainname,omitempty"`
+	User         string              `json:"User,omitempty"`
+	AttachStdin  bool                `json:"AttachStdin,omitempty"`
+	AttachStdout bool                `json:"AttachStdout,omitempty"`
+	AttachStderr bool                `json:"AttachStderr,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	Tty          bool                `json:"Tty,omitempty"`
+	OpenStdin    bool                `json:"OpenStdin,omitempty"`
+	StdinOnce    bool                `json:"StdinOnce,omitempty"`
+	Env          []string            `json:"Env,omitempty"`
+	Cmd          []string            `json:"Cmd,omitempty"`
+	Healthcheck  *HealthConfig       `json:"Healthcheck,omitempty"`
+	Image        string              `json:"Image,omitempty"`
+	Volumes      map[string]struct{} `json:"Volumes,omitempty"`
+	WorkingDir   string              `json:"WorkingDir,omitempty"`
+	Entrypoint   []string            `json:"Entrypoint,omitempty"`
+	Labels       map[string]string   `json:"Labels,omitempty"`
+	StopSignal   string              `json:"StopSignal,omitempty"`
+	StopTimeout  *int                `json:"StopTimeout,omitempty"`
+}
+
+// HealthConfig is the healthcheck of a container. Durations are in nanoseconds.
+type HealthConfig struct {
+	Test        []string `json:"Test,omitempty"`
+	Interval    int64    `json:"Interval,omitempty"`
+	Timeout     int64    `json:"Timeout,omitempty"`
+	StartPeriod int64    `json:"StartPeriod,omitempty"`
+	Retries     int      `json:"Retries,omitempty"`
+}
+
+// HostConfig is the configuration of a container that depends on the host.
+type HostConfig struct {
+	Binds           []string                 `json:"Binds,omitempty"`
+	NetworkMode     string                   `json:"NetworkMode,omitempty"`
+	PortBindings    map[string][]PortBinding `json:"PortBindings,omitempty"`
+	RestartPolicy   RestartPolicy            `json:"RestartPolicy"`
+	AutoRemove      bool                     `json:"AutoRemove,omitempty"`
+	Privileged      bool                     `json:"Privileged,omitempty"`
+	PublishAllPorts bool                     `json:"PublishAllPorts,omitempty"`
+	ExtraHosts      []string                 `json:"ExtraHosts,omitempty"`
+	CapAdd          []string                 `json:"CapAdd,omitempty"`
+	CapDrop         []string                 `json:"CapDrop,omitempty"`
+	Mounts          []Mount                  `json:"Mounts,omitempty"`
+	Memory          int64                    `json:"Memory,omitempty"`
+	NanoCPUs        int64                    `json:"NanoCpus,omitempty"`
+	Init            *bool                    `json:"Init,omitempty"`
+}
+
+// PortBinding binds a port of a container to a port of the host.
+type PortBinding struct {
+	HostIP   string `json:"HostIp,omitempty"`
+	HostPort string `json:"HostPort,omitempty"`
+}
+
+// RestartPolicy is the policy to restart a container when it exits.
+type RestartPolicy struct {
+	// Name is "", "no", "always", "unless-stopped" or "on-failure".
+	Name              string `json:"Name,omitempty"`
+	MaximumRetryCount int    `json:"MaximumRetryCount,omitempty"`
+}
+
+// Mount is a mount of a container, with the same format as "docker run --mount".
+type Mount struct {
+	// Type is "bind", "volume", "tmpfs" or "npipe".
+	Type     string `json:"Type,omitempty"`
+	Source   string `json:"Source,omitempty"`
+	Target   string `json:"Target,omitempty"`
+	ReadOnly bool   `json:"ReadOnly,omitempty"`
+}
+
+// NetworkingConfig is the configuration of the networks a container is connected to on creation.
+type NetworkingConfig struct {
+	EndpointsConfig map[string]*EndpointSettings `json:"EndpointsConfig,omitempty"`
+}
+
+// EndpointSettings is the configuration of the connection of a container to a network.
+type EndpointSettings struct {
+	Aliases     []string `json:"Aliases,omitempty"`
+	NetworkID   string   `json:"NetworkID,omitempty"`
+	EndpointID  string   `json:"EndpointID,omitempty"`
+	Gateway     string   `json:"Gateway,omitempty"`
+	IPAddress   string   `json:"IPAddress,omitempty"`
+	IPPrefixLen int      `json:"IPPrefixLen,omitempty"`
+	MacAddress  string   `json:"MacAddress,omitempty"`
+	DNSNames    []string `json:"DNSNames,omitempty"`
+}
+
+// ContainerCreateResponse is the response of the creation of a container.
+type ContainerCreateResponse struct {
+	ID       string   `json:"Id"`
+	Warnings []string `json:"Warnings"`
+}
+
+// ContainerJSON is the low-level information of a container, as returned by "docker inspect".
+type ContainerJSON struct {
+	ID              string           `json:"Id"`
+	Created         string           `json:"Created"`
+	Path            string           `json:"Path"`
+	Args            []string         `json:"Args"`
+	State           *ContainerState  `json:"State"`
+	Image           string           `json:"Image"`
+	Name            string           `json:"Name"`
+	RestartCount    int              `json:"RestartCount"`
+	Driver          string           `json:"Driver"`
+	Platform        string           `json:"Platform"`
+	Mounts          []MountPoint     `json:"Mounts"`
+	Config          *ContainerConfig `json:"Config"`
+	HostConfig      *HostConfig      `json:"HostConfig"`
+	NetworkSettings *NetworkSettings `json:"NetworkSettings"`
+}
+
+// ContainerState is the state of a container.
+type ContainerState struct {
+	// Status is "created", "running", "paused", "restarting", "removing", "exited" or "dead".
+	Status     string  `json:"Status"`
+	Running    bool    `json:"Running"`
+	Paused     bool    `json:"Paused"`
+	Restarting bool    `json:"Restarting"`
+	OOMKilled  bool    `json:"OOMKilled"`
+	Dead       bool    `json:"Dead"`
+	Pid        int     `json:"Pid"`
+	ExitCode   int     `json:"ExitCode"`
+	Error      string  `json:"Error"`
+	StartedAt  string  `json:"StartedAt"`
+	FinishedAt string  `json:"FinishedAt"`
+	Health     *Health `json:"Health,omitempty"`
+}
+
+// Health is the health of a container with a healthcheck.
+type Health struct {
+	// Status is "starting", "healthy" or "unhealthy".
+	Status        string `json:"Status"`
+	FailingStreak int    `json:"FailingStreak"`
+}
+
+// MountPoint is a mount of a running container.
+type MountPoint struct {
+	Type        string `json:"Type,omitempty"`
+	Name        string `json:"Name,omitempty"`
+	Source      string `json:"Source"`
+	Destination string `json:"Destination"`
+	Driver      string `json:"Driver,omitempty"`
+	Mode        string `json:"Mode"`
+	RW          bool   `json:"RW"`
+}
+
+// NetworkSettings are the network settings of a container.
+type NetworkSettings struct {
+	Ports    map[string][]PortBinding     `json:"Ports"`
+	Networks map[string]*EndpointSettings `json:"Networks"`
+}
+
+// Container is the summary of a container, as returned by "docker ps".
+type Container struct {
+	ID              string                  `json:"Id"`
+	Names           []string                `json:"Names"`
+	Image           string                  `json:"Image"`
+	ImageID         string                  `json:"ImageID"`
+	Command         string                  `json:"Command"`
+	Created         int64                   `json:"Created"`
+	Ports           []Port                  `json:"Ports"`
+	SizeRw          int64                   `json:"SizeRw,omitempty"`
+	SizeRootFs      int64                   `json:"SizeRootFs,omitempty"`
+	Labels          map[string]string       `json:"Labels"`
+	State           string                  `json:"State"`
+	Status          string                  `json:"Status"`
+	Mounts          []MountPoint            `json:"Mounts"`
+	NetworkSettings *SummaryNetworkSettings `json:"NetworkSettings"`
+}
+
+// Port is a port of a container, as returned in its summary.
+type Port struct {
+	IP          string `json:"IP,omitempty"`
+	PrivatePort uint16 `json:"PrivatePort"`
+	PublicPort  uint16 `json:"PublicPort,omitempty"`
+	Type        string `json:"Type"`
+}
+
+// SummaryNetworkSettings are the networks of a container, as returned in its summary.
+type SummaryNetworkSettings struct {
+	Networks map[string]*EndpointSettings `json:"Networks"`
+}
@@ -0,0 +1,121 @@
+package dockerclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// progressBarWidth is the width of the progress bars, between the brackets
+const progressBarWidth = 50
+
+// DisplayMessages reads the stream to its end, writing its messages to out like the Docker CLI.
+//
+// On a terminal, the messages of each ID, e.g. of each layer on pull, update the same line,
+// with a progress bar. Otherwise, the messages are written one per line, skipping those
+// reporting progress. It returns the first error reported by the stream, if any.
+func DisplayMessages(out io.Writer, stream *MessageStream, isTerminal bool) error {
+	// lines are the lines of the IDs, counted from the first one written after
+	// the last message without ID
+	lines := map[string]int{}
+
+	for {
+		msg, err := stream.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if msg.Stream != "" {
+			lines = map[string]int{}
+			if _, err := io.WriteString(out, msg.Stream); err != nil {
+				return fmt.Errorf("display message: %w", err)
+			}
+			continue
+		}
+
+		progress := progressString(msg)
+		if msg.Status == "" && progress == "" {
+			continue // e.g. aux messages
+		}
+
+		if !isTerminal {
+			if progress != "" {
+				continue
+			}
+			if _, err := io.WriteString(out, messageLine(msg, "")+"\n"); err != nil {
+				return fmt.Errorf("display message: %w", err)
+			}
+			continue
+		}
+
+		line := "\x1b[2K\r" + messageLine(msg, progress)
+
+		i, ok := lines[msg.ID]
+		switch {
+		case msg.ID == "":
+			lines = map[string]int{}
+			line += "\n"
+		case !ok:
+			lines[msg.ID] = len(lines)
+			line += "\n"
+		default:
+			// move the cursor up to the line of the ID, and back down to the line after the last ID
+			up := len(lines) - i
+			line = fmt.Sprintf("\x1b[%dA%s\x1b[%dB\r", up, line, up)
+		}
+
+		if _, err := io.WriteString(out, line); err != nil {
+			return fmt.Errorf("display message: %w", err)
+		}
+	}
+}
+
+// messageLine returns the line of a message, e.g. "a1b2c3: Downloading [==>  ]  1MB/10MB".
+func messageLine(msg JSONMessage, progress string) string {
+	var sb strings.Builder
+	if msg.ID != "" {
+		sb.WriteString(msg.ID + ": ")
+	}
+	sb.WriteString(msg.Status)
+	if progress != "" {
+		sb.WriteString(" " + progress)
+	}
+
+	return sb.String()
+}
+
+// progressString returns the progress of a message as text, like the Docker CLI:
+// a progress bar with the transferred and total sizes, or the transferred size only
+// if the total is unknown. It falls back to the progress rendered by the Docker daemon.
+func progressString(msg JSONMessage) string {
+	p := msg.Progress
+	if p == nil || (p.Current <= 0 && p.Total <= 0) {
+		return msg.ProgressMessage
+	}
+
+	if p.Total <= 0 {
+		return fmt.Sprintf("%8v", humanSize(p.Current))
+	}
+
+	filled := min(int(float64(p.Current)/float64(p.Total)*progressBarWidth), progressBarWidth)
+	bar := "[" + strings.Repeat("=", filled) + ">" + strings.Repeat(" ", progressBarWidth-filled) + "] "
+
+	return bar + fmt.Sprintf("%8v/%v", humanSize(p.Current), humanSize(p.Total))
+}
+
+// humanSize returns the size in bytes with decimal units and 4 significant digits, e.g. "12.35MB".
+func humanSize(size int64) string {
+	units := []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+
+	s, i := float64(size), 0
+	for s >= 1000 && i < len(units)-1 {
+		s /= 1000
+		i++
+	}
+
+	return fmt.Sprintf("%.4g%s", s, units[i])
+}
@@ -0,0 +1,102 @@
+package dockerclient
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const pullStream = `{"status":"Pulling from library/nginx","id":"latest"}
+{"status":"Pulling fs layer","id":"aaa"}
+{"status":"Pulling fs layer","id":"bbb"}
+{"status":"Downloading","progressDetail":{"current":500,"total":1000},"id":"aaa"}
+{"status":"Pull complete","id":"bbb"}
+{"aux":{"ID":"sha256:abc"}}
+{"status":"Digest: sha256:abc"}
+`
+
+func TestDisplayMessages(t *testing.T) {
+	t.Run("terminal", func(tt *testing.T) {
+		var out bytes.Buffer
+		require.NoError(tt, DisplayMessages(&out, NewMessageStream(io.NopCloser(strings.NewReader(pullStream))), true))
+
+		bar := "[=========================>                         ]     500B/1kB"
+		require.Equal(tt, "\x1b[2K\rlatest: Pulling from library/nginx\n"+
+			"\x1b[2K\raaa: Pulling fs layer\n"+
+			"\x1b[2K\rbbb: Pulling fs layer\n"+
+			"\x1b[2A\x1b[2K\raaa: Downloading "+bar+"\x1b[2B\r"+
+			"\x1b[1A\x1b[2K\rbbb: Pull complete\x1b[1B\r"+
+			"\x1b[2K\rDigest: sha256:abc\n", out.String())
+	})
+
+	t.Run("not-terminal", func(tt *testing.T) {
+		var out bytes.Buffer
+		require.NoError(tt, DisplayMessages(&out, NewMessageStream(io.NopCloser(strings.NewReader(pullStream))), false))
+
+		require.Equal(tt, `latest: Pulling from library/nginx
+aaa: Pulling fs layer
+bbb: Pulling fs layer
+bbb: Pull complete
+Digest: sha256:abc
+`, out.String())
+	})
+
+	t.Run("build", func(tt *testing.T) {
+		var out bytes.Buffer
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"stream":"Step 1/2 : FROM alpine\n"}
+{"stream":"Successfully built abc\n"}
+`)))
+		require.NoError(tt, DisplayMessages(&out, stream, true))
+		require.Equal(tt, "Step 1/2 : FROM alpine\nSuccessfully built abc\n", out.String())
+	})
+
+	t.Run("error", func(tt *testing.T) {
+		var out bytes.Buffer
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"status":"Pulling fs layer","id":"aaa"}
+{"errorDetail":{"message":"unexpected EOF"},"error":"unexpected EOF"}
+{"status":"Pull complete","id":"aaa"}
+`)))
+		err := DisplayMessages(&out, stream, false)
+		require.EqualError(tt, err, "unexpected EOF")
+		require.Equal(tt, "aaa: Pulling fs layer\n", out.String())
+	})
+}
+
+func TestProgressString(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      JSONMessage
+		expected string
+	}{
+		{name: "none", msg: JSONMessage{}, expected: ""},
+		{name: "daemon", msg: JSONMessage{ProgressMessage: "[==>   ] 1MB/2MB"}, expected: "[==>   ] 1MB/2MB"},
+		{name: "empty", msg: JSONMessage{Progress: &JSONProgress{}, ProgressMessage: "1MB"}, expected: "1MB"},
+		{name: "unknown-total", msg: JSONMessage{Progress: &JSONProgress{Current: 12345678}}, expected: " 12.35MB"},
+		{
+			name:     "done",
+			msg:      JSONMessage{Progress: &JSONProgress{Current: 2048, Total: 2048}},
+			expected: "[" + strings.Repeat("=", 50) + ">]  2.048kB/2.048kB",
+		},
+		{
+			name:     "overflow",
+			msg:      JSONMessage{Progress: &JSONProgress{Current: 4096, Total: 2048}},
+			expected: "[" + strings.Repeat("=", 50) + ">]  4.096kB/2.048kB",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tt *testing.T) {
+			require.Equal(tt, tc.expected, progressString(tc.msg))
+		})
+	}
+}
+
+func TestHumanSize(t *testing.T) {
+	require.Equal(t, "0B", humanSize(0))
+	require.Equal(t, "999B", humanSize(999))
+	require.Equal(t, "1kB", humanSize(1000))
+	require.Equal(t, "1.5GB", humanSize(1_500_000_000))
+}
@@ -0,0 +1,99 @@
+package dockerclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize is the maximum size of the error responses read from the Docker daemon
+const maxErrorBodySize = 1 << 20
+
+// Errors matching the HTTP status codes of the responses of the Docker daemon,
+// to be checked with [errors.Is] on the errors returned by the [Client].
+var (
+	ErrInvalidParameter = errors.New("invalid parameter")
+	ErrUnauthorized     = errors.New("unauthorized")
+	ErrForbidden        = errors.New("forbidden")
+	ErrNotFound         = errors.New("not found")
+	ErrConflict         = errors.New("conflict")
+	ErrNotModified      = errors.New("not modified")
+	ErrSystem           = errors.New("system error")
+	ErrNotImplemented   = errors.New("not implemented")
+	ErrUnavailable      = errors.New("unavailable")
+)
+
+// ErrConnectionFailed is returned when the Docker daemon can't be reached.
+var ErrConnectionFailed = errors.New("cannot connect to the Docker daemon")
+
+// APIError is the error returned when the Docker daemon answers a request with an error status.
+// It matches the error of its status code with [errors.Is], e.g. [ErrNotFound] for 404.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Message is the error message of the Docker daemon.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("docker daemon: %s (status %d)", e.Message, e.StatusCode)
+}
+
+// Is reports whether the target is the error of the status code.
+func (e *APIError) Is(target error) bool {
+	err := statusError(e.StatusCode)
+	return err != nil && err == target //nolint:errorlint // The sentinels are compared by identity.
+}
+
+// statusError returns the error of the given HTTP status code, or nil if there is none.
+func statusError(code int) error {
+	switch code {
+	case http.StatusBadRequest:
+		return ErrInvalidParameter
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusNotModified:
+		return ErrNotModified
+	case http.StatusInternalServerError:
+		return ErrSystem
+	case http.StatusNotImplemented:
+		return ErrNotImplemented
+	case http.StatusServiceUnavailable:
+		return ErrUnavailable
+	default:
+		return nil
+	}
+}
+
+// newAPIError reads the error response of the Docker daemon, which is a JSON object
+// with a "message", or plain text for older daemons and proxies.
+func newAPIError(resp *http.Response) error {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		return fmt.Errorf("read error response: %w", err)
+	}
+
+	var errResp struct {
+		Message string `json:"message"`
+	}
+	msg := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &errResp) == nil && errResp.Message != "" {
+		msg = errResp.Message
+	}
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+
+	return &APIError{StatusCode: resp.StatusCode, Message: msg}
+}
@@ -0,0 +1,44 @@
+package dockerclient
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	tests := map[int]error{
+		http.StatusBadRequest:          ErrInvalidParameter,
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusForbidden:           ErrForbidden,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusConflict:            ErrConflict,
+		http.StatusNotModified:         ErrNotModified,
+		http.StatusInternalServerError: ErrSystem,
+		http.StatusNotImplemented:      ErrNotImplemented,
+		http.StatusServiceUnavailable:  ErrUnavailable,
+	}
+
+	for code, want := range tests {
+		t.Run(http.StatusText(code), func(tt *testing.T) {
+			err := error(&APIError{StatusCode: code, Message: "message"})
+			require.ErrorIs(tt, err, want)
+
+			for _, other := range tests {
+				if !errors.Is(other, want) {
+					require.NotErrorIs(tt, err, other)
+				}
+			}
+		})
+	}
+
+	t.Run("unmapped", func(tt *testing.T) {
+		err := error(&APIError{StatusCode: http.StatusTeapot, Message: "message"})
+		for _, other := range tests {
+			require.NotErrorIs(tt, err, other)
+		}
+		require.NotErrorIs(tt, err, nil)
+	})
+}
@@ -0,0 +1,222 @@
+package dockerclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// eventsRetryDelay is the delay before the first reconnection to the events stream,
+	// doubled on each failed attempt up to eventsMaxRetryDelay
+	eventsRetryDelay = 100 * time.Millisecond
+
+	// eventsMaxRetryDelay is the maximum delay between reconnections to the events stream
+	eventsMaxRetryDelay = 5 * time.Second
+)
+
+// EventType is the type of the object an event refers to.
+type EventType string
+
+// Types of the events reported by the Docker daemon.
+const (
+	ContainerEventType EventType = "container"
+	ImageEventType     EventType = "image"
+	NetworkEventType   EventType = "network"
+	VolumeEventType    EventType = "volume"
+	DaemonEventType    EventType = "daemon"
+)
+
+// Event is an event reported by the Docker daemon, like "docker events".
+type Event struct {
+	// Type is the type of the object the event refers to.
+	Type EventType `json:"Type"`
+
+	// Action is the action of the event, e.g. "start" or "die" for containers.
+	Action string `json:"Action"`
+
+	// Actor is the object the event refers to.
+	Actor EventActor `json:"Actor"`
+
+	// Scope is "local" for events of the Docker daemon, or "swarm" for events of the cluster.
+	Scope string `json:"scope"`
+
+	// Time is the time of the event, in seconds since the Unix epoch.
+	Time int64 `json:"time"`
+
+	// TimeNano is the time of the event, in nanoseconds since the Unix epoch.
+	TimeNano int64 `json:"timeNano"`
+}
+
+// Timestamp returns the time of the event.
+func (e Event) Timestamp() time.Time {
+	if e.TimeNano == 0 {
+		return time.Unix(e.Time, 0)
+	}
+	return time.Unix(0, e.TimeNano)
+}
+
+// EventActor is the object an event refers to.
+type EventActor struct {
+	// ID is the ID of the object, e.g. of the container.
+	ID string `json:"ID"`
+
+	// Attributes are the attributes of the object, e.g. "name", "image" or "exitCode" for containers.
+	Attributes map[string]string `json:"Attributes"`
+}
+
+// Events subscribes to the events of the Docker daemon matching the given filters, e.g.
+// {"type": {"container"}, "event": {"die"}}, like "docker events". The events are sent
+// on the returned channel until the context is done.
+//
+// If the connection to the Docker daemon drops, e.g. because it's restarted, the client
+// reconnects with an increasing delay, and resumes the stream from the time of the last
+// event received, or the time of the Docker daemon when the stream was opened if no event
+// was received yet, skipping the events already sent. The Docker daemon keeps a limited
+// buffer of past events, so events may still be missed after a long disconnection.
+//
+// The error channel receives the error ending the subscription: the error of the context
+// once it's done, or an error of the Docker daemon that retrying wouldn't fix, e.g. an
+// invalid filter. No events are sent afterwards.
+func (c *Client) Events(ctx context.Context, filters Filters) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error, 1)
+
+	query := url.Values{}
+	if err := filters.setQuery(query); err != nil {
+		errs <- fmt.Errorf("events: %w", err)
+		return events, errs
+	}
+
+	go func() {
+		errs <- c.watchEvents(ctx, query, events)
+	}()
+
+	return events, errs
+}
+
+// watchEvents streams the events to the channel, reconnecting until the context is done
+// or the Docker daemon reports an error that is not retryable.
+func (c *Client) watchEvents(ctx context.Context, query url.Values, events chan<- Event) error {
+	var cursor eventCursor
+	delay := eventsRetryDelay
+
+	for {
+		connected, err := c.streamEvents(ctx, query, &cursor, events)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("events: %w", ctxErr)
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError {
+			return fmt.Errorf("events: %w", err)
+		}
+
+		if connected {
+			delay = eventsRetryDelay
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("events: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		delay = min(delay*2, eventsMaxRetryDelay)
+	}
+}
+
+// streamEvents sends the "GET /events" request, resuming after the cursor, and sends the
+// events to the channel until the stream ends. It reports whether the stream was opened.
+func (c *Client) streamEvents(ctx context.Context, query url.Values, cursor *eventCursor, events chan<- Event) (bool, error) {
+	query = maps.Clone(query)
+	if cursor.timeNano > 0 {
+		query.Set("since", fmt.Sprintf("%d.%09d", cursor.timeNano/int64(time.Second), cursor.timeNano%int64(time.Second)))
+	}
+
+	resp, err := c.get(ctx, "/events", query)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	// resume from the time of the Docker daemon if the stream drops before the first event
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		cursor.seed(date)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var event Event
+		if err := dec.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return true, nil
+			}
+			return true, fmt.Errorf("decode event: %w", err)
+		}
+
+		if !cursor.advance(event) {
+			continue
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return true, ctx.Err()
+		}
+	}
+}
+
+// eventKey identifies an event among the events with the same time
+type eventKey struct {
+	eventType EventType
+	action    string
+	actorID   string
+}
+
+// eventCursor is the position of the last event received. The Docker daemon resends
+// the events with the same time as the "since" parameter, so the events received
+// at that time are kept to skip them on resume.
+type eventCursor struct {
+	timeNano int64
+	seen     map[eventKey]bool
+}
+
+// seed moves the cursor to the given time, if no event was received yet. The time must
+// not be later than the opening of the stream, e.g. the Date header of the response,
+// which is truncated to the second.
+func (c *eventCursor) seed(t time.Time) {
+	if c.seen != nil {
+		return
+	}
+
+	c.timeNano = t.UnixNano()
+	c.seen = map[eventKey]bool{}
+}
+
+// advance moves the cursor to the given event, reporting whether it's a new one.
+func (c *eventCursor) advance(event Event) bool {
+	timeNano := event.Timestamp().UnixNano()
+	if timeNano < c.timeNano {
+		return false
+	}
+
+	key := eventKey{eventType: event.Type, action: event.Action, actorID: event.Actor.ID}
+	if timeNano > c.timeNano || c.seen == nil {
+		c.timeNano = timeNano
+		c.seen = map[eventKey]bool{}
+	} else if c.seen[key] {
+		return false
+	}
+	c.seen[key] = true
+
+	return true
+}
@@ -0,0 +1,337 @@
+package dockerclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdelapenya/docker-sdk-go/dockercontext"
+)
+
+func TestClient_Events(t *testing.T) {
+	// the events are later than the Date header of the responses, as for a real Docker daemon
+	base := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	t.Run("resume", func(tt *testing.T) {
+		daemon := newFakeEventsDaemon(tt, 0)
+		cli := newEventsClient(tt, daemon)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		filters := Filters{}
+		filters.Add("type", "container", "daemon")
+		events, errs := cli.Events(ctx, filters)
+		daemon.waitConnections(tt, 1)
+
+		first := []Event{
+			containerEvent("start", "aaa", base.Add(time.Second)),
+			containerEvent("die", "aaa", base.Add(2*time.Second)),
+		}
+		daemon.emit(first...)
+		require.Equal(tt, first, receiveEvents(tt, events, errs, 2))
+
+		// the Docker daemon is restarted, and resends the events since the given time, inclusive
+		daemon.restart()
+		second := []Event{
+			containerEvent("die", "bbb", base.Add(2*time.Second)),
+			{Type: DaemonEventType, Action: "reload", Actor: EventActor{ID: "daemon"}, Scope: "local", Time: base.Unix() + 3, TimeNano: base.Add(3 * time.Second).UnixNano()},
+		}
+		daemon.emit(second...)
+		require.Equal(tt, second, receiveEvents(tt, events, errs, 2))
+
+		cancel()
+		require.ErrorIs(tt, <-errs, context.Canceled)
+
+		queries := daemon.queries()
+		require.Len(tt, queries, 2)
+		require.Equal(tt, url.Values{"filters": {`{"type":{"container":true,"daemon":true}}`}}, queries[0])
+		require.Equal(tt, url.Values{
+			"filters": {`{"type":{"container":true,"daemon":true}}`},
+			"since":   {strconv.FormatInt(base.Unix()+2, 10) + ".000000000"},
+		}, queries[1])
+	})
+
+	t.Run("outage-before-first-event", func(tt *testing.T) {
+		daemon := newFakeEventsDaemon(tt, 0)
+		cli := newEventsClient(tt, daemon)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, errs := cli.Events(ctx, nil)
+		daemon.waitConnections(tt, 1)
+
+		// the container dies while the Docker daemon is restarted, before any event was received
+		daemon.restart()
+		died := containerEvent("die", "aaa", time.Now())
+		daemon.emit(died)
+
+		require.Equal(tt, []Event{died}, receiveEvents(tt, events, errs, 1))
+
+		queries := daemon.queries()
+		require.Len(tt, queries, 2)
+		require.Empty(tt, queries[0])
+		require.NotEmpty(tt, queries[1].Get("since")) // from the Date header of the first response
+	})
+
+	t.Run("unavailable", func(tt *testing.T) {
+		daemon := newFakeEventsDaemon(tt, 2)
+		cli := newEventsClient(tt, daemon)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, errs := cli.Events(ctx, nil)
+		daemon.waitConnections(tt, 1)
+
+		created := containerEvent("create", "aaa", base.Add(time.Second))
+		daemon.emit(created)
+		require.Equal(tt, []Event{created}, receiveEvents(tt, events, errs, 1))
+		require.Len(tt, daemon.queries(), 3) // two failures, and the stream
+	})
+
+	t.Run("invalid-filter", func(tt *testing.T) {
+		mux := newDaemonMux(tt, DefaultAPIVersion)
+		mux.HandleFunc("GET /v"+DefaultAPIVersion+"/events", errorHandler(http.StatusBadRequest, "invalid filter 'foo'"))
+		cli := newTestClient(tt, serveDaemon(tt, mux), "")
+
+		_, errs := cli.Events(context.Background(), Filters{"foo": {"bar"}})
+
+		err := <-errs
+		require.ErrorIs(tt, err, ErrInvalidParameter)
+		require.ErrorContains(tt, err, "invalid filter 'foo'")
+	})
+
+	t.Run("not-reachable", func(tt *testing.T) {
+		// nothing listens on port 1, so every connection is refused
+		cli := newTestClient(tt, dockercontext.DockerEndpoint{Host: "tcp://127.0.0.1:1"}, "")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+
+		_, errs := cli.Events(ctx, nil)
+		require.ErrorIs(tt, <-errs, context.DeadlineExceeded)
+	})
+}
+
+func TestEventCursor_advance(t *testing.T) {
+	var cursor eventCursor
+	at := func(secs int64) time.Time { return time.Unix(secs, 0) }
+
+	require.True(t, cursor.advance(containerEvent("start", "aaa", at(1))))
+	require.True(t, cursor.advance(containerEvent("start", "bbb", at(1))))
+	require.False(t, cursor.advance(containerEvent("start", "aaa", at(1))))
+	require.True(t, cursor.advance(containerEvent("die", "aaa", at(1))))
+	require.True(t, cursor.advance(containerEvent("die", "aaa", at(2))))
+	require.False(t, cursor.advance(containerEvent("die", "bbb", at(1))))
+
+	// events without time in nanoseconds, from older Docker daemons
+	require.True(t, cursor.advance(Event{Type: ContainerEventType, Action: "stop", Time: 2}))
+	require.False(t, cursor.advance(Event{Type: ContainerEventType, Action: "stop", Time: 2}))
+	require.Equal(t, 2*int64(time.Second), cursor.timeNano)
+}
+
+func TestEventCursor_seed(t *testing.T) {
+	t.Run("no-events", func(tt *testing.T) {
+		var cursor eventCursor
+		cursor.seed(time.Unix(10, 0))
+		cursor.seed(time.Unix(20, 0)) // the first time is kept
+
+		require.Equal(tt, 10*int64(time.Second), cursor.timeNano)
+		require.False(tt, cursor.advance(containerEvent("die", "aaa", time.Unix(9, 0))))
+		require.True(tt, cursor.advance(containerEvent("die", "aaa", time.Unix(10, 0))))
+	})
+
+	t.Run("after-events", func(tt *testing.T) {
+		var cursor eventCursor
+		require.True(tt, cursor.advance(containerEvent("die", "aaa", time.Unix(10, 0))))
+
+		cursor.seed(time.Unix(20, 0))
+		require.Equal(tt, 10*int64(time.Second), cursor.timeNano)
+	})
+}
+
+func TestEvent_Timestamp(t *testing.T) {
+	require.Equal(t, time.Unix(1, 5), Event{Time: 1, TimeNano: int64(time.Second) + 5}.Timestamp())
+	require.Equal(t, time.Unix(1, 0), Event{Time: 1}.Timestamp())
+}
+
+// fakeEventsDaemon serves the events of a fake Docker daemon. Like a real one, it keeps
+// a log of the events, sent on the connections opened with a "since" parameter, and sends
+// the new events to the open connections.
+type fakeEventsDaemon struct {
+	t *testing.T
+
+	// failures is the number of requests failing with 503 Service Unavailable, before the first stream
+	failures int
+
+	mu          sync.Mutex
+	log         []Event
+	connections map[chan Event]bool
+	opened      int
+	restarted   chan struct{}
+	requests    []url.Values
+}
+
+// newFakeEventsDaemon returns a fake Docker daemon serving the events, after the given number of failures.
+func newFakeEventsDaemon(t *testing.T, failures int) *fakeEventsDaemon {
+	t.Helper()
+
+	return &fakeEventsDaemon{t: t, failures: failures, connections: map[chan Event]bool{}, restarted: make(chan struct{})}
+}
+
+func (d *fakeEventsDaemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	d.requests = append(d.requests, r.URL.Query())
+	if len(d.requests) <= d.failures {
+		d.mu.Unlock()
+		errorHandler(http.StatusServiceUnavailable, "daemon is starting")(w, r)
+		return
+	}
+
+	var since int64
+	if s := r.URL.Query().Get("since"); s != "" {
+		secs, nanos, _ := strings.Cut(s, ".")
+		sec, err := strconv.ParseInt(secs, 10, 64)
+		require.NoError(d.t, err)
+		nsec, err := strconv.ParseInt(nanos, 10, 64)
+		require.NoError(d.t, err)
+		since = sec*int64(time.Second) + nsec
+	}
+
+	var past []Event
+	if since > 0 {
+		for _, e := range d.log {
+			if e.TimeNano >= since {
+				past = append(past, e)
+			}
+		}
+	}
+
+	live := make(chan Event, 16)
+	d.connections[live] = true
+	d.opened++
+	restarted := d.restarted
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		delete(d.connections, live)
+		d.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+
+	enc := json.NewEncoder(w)
+	for _, e := range past {
+		require.NoError(d.t, enc.Encode(e))
+	}
+	w.(http.Flusher).Flush()
+
+	for {
+		select {
+		case e := <-live:
+			require.NoError(d.t, enc.Encode(e))
+			w.(http.Flusher).Flush()
+		case <-restarted:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// emit logs the given events, and sends them to the open connections.
+func (d *fakeEventsDaemon) emit(events ...Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.log = append(d.log, events...)
+	for live := range d.connections {
+		for _, e := range events {
+			live <- e
+		}
+	}
+}
+
+// restart closes the open connections, as a restart of the Docker daemon does.
+func (d *fakeEventsDaemon) restart() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	close(d.restarted)
+	d.restarted = make(chan struct{})
+	clear(d.connections)
+}
+
+// waitConnections waits until the given number of streams were opened.
+func (d *fakeEventsDaemon) waitConnections(t *testing.T, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return d.opened >= n
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+// queries returns the queries of the requests received.
+func (d *fakeEventsDaemon) queries() []url.Values {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return slices.Clone(d.requests)
+}
+
+// newEventsClient returns a client of a fake Docker daemon serving the events with the given daemon.
+func newEventsClient(t *testing.T, daemon *fakeEventsDaemon) *Client {
+	t.Helper()
+
+	mux := newDaemonMux(t, DefaultAPIVersion)
+	mux.Handle("GET /v"+DefaultAPIVersion+"/events", daemon)
+
+	return newTestClient(t, serveDaemon(t, mux), "")
+}
+
+// containerEvent returns a container event at the given time.
+func containerEvent(action, id string, at time.Time) Event {
+	return Event{
+		Type:     ContainerEventType,
+		Action:   action,
+		Actor:    EventActor{ID: id, Attributes: map[string]string{"name": "container-" + id}},
+		Scope:    "local",
+		Time:     at.Unix(),
+		TimeNano: at.UnixNano(),
+	}
+}
+
+// receiveEvents receives the given number of events, failing if the subscription ends before.
+func receiveEvents(t *testing.T, events <-chan Event, errs <-chan error, n int) []Event {
+	t.Helper()
+
+	received := make([]Event, 0, n)
+	for len(received) < n {
+		select {
+		case e := <-events:
+			received = append(received, e)
+		case err := <-errs:
+			require.FailNow(t, "subscription ended", err.Error())
+		case <-time.After(10 * time.Second):
+			require.FailNow(t, "timeout waiting for events", "received %d of %d", len(received), n)
+		}
+	}
+
+	return received
+}
@@ -0,0 +1,48 @@
+package dockerclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Filters are the filters of the list and events requests, mapping each filter
+// to its accepted values, e.g. {"status": {"running", "paused"}, "label": {"app=web"}}.
+type Filters map[string][]string
+
+// Add adds the given values to the filter with the given key.
+func (f Filters) Add(key string, values ...string) {
+	f[key] = append(f[key], values...)
+}
+
+// setQuery sets the filters in the "filters" query parameter, with the JSON format
+// of the Docker daemon: {"status":{"running":true}}. Empty filters are not set.
+func (f Filters) setQuery(query url.Values) error {
+	if len(f) == 0 {
+		return nil
+	}
+
+	args := make(map[string]map[string]bool, len(f))
+	for key, values := range f {
+		if len(values) == 0 {
+			continue
+		}
+
+		args[key] = make(map[string]bool, len(values))
+		for _, v := range values {
+			args[key][v] = true
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("encode filters: %w", err)
+	}
+	query.Set("filters", string(data))
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package dockerclient
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilters_setQuery(t *testing.T) {
+	t.Run("empty", func(tt *testing.T) {
+		query := url.Values{}
+		require.NoError(tt, Filters(nil).setQuery(query))
+		require.NoError(tt, Filters{"label": nil}.setQuery(query))
+		require.Empty(tt, query)
+	})
+
+	t.Run("values", func(tt *testing.T) {
+		filters := Filters{}
+		filters.Add("status", "running", "paused")
+		filters.Add("label", "app=web")
+		filters.Add("status", "running")
+		filters.Add("name")
+
+		query := url.Values{}
+		require.NoError(tt, filters.setQuery(query))
+		require.JSONEq(tt, `{"label":{"app=web":true},"status":{"paused":true,"running":true}}`, query.Get("filters"))
+	})
+}
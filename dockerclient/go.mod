@@ -0,0 +1,20 @@
+module github.com/mdelapenya/docker-sdk-go/dockerclient
+
+go 1.23.6
+
+replace (
+	github.com/mdelapenya/docker-sdk-go/dockerconfig => ../dockerconfig
+	github.com/mdelapenya/docker-sdk-go/dockercontext => ../dockercontext
+)
+
+require (
+	github.com/mdelapenya/docker-sdk-go/dockerconfig v0.1.0
+	github.com/mdelapenya/docker-sdk-go/dockercontext v0.1.0
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
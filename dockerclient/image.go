@@ -0,0 +1,196 @@
+package dockerclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+)
+
+const (
+	// defaultRegistry is the registry of the image references without a registry host
+	defaultRegistry = "docker.io"
+
+	// defaultTag is the tag of the image references without a tag or digest
+	defaultTag = "latest"
+
+	// registryAuthHeader is the header of the encoded registry credentials
+	registryAuthHeader = "X-Registry-Auth"
+)
+
+// PullOptions configures how an image is pulled.
+type PullOptions struct {
+	// All pulls all the tags of the repository of the image, ignoring its tag.
+	All bool
+
+	// Platform is the platform of the image to pull, e.g. "linux/arm64".
+	// It defaults to the platform of the Docker daemon.
+	Platform string
+
+	// RegistryAuth is the encoded registry credentials of the X-Registry-Auth header,
+	// see [EncodeRegistryAuth]. If empty, the credentials of the registry of the image
+	// are resolved with [dockerconfig.GetRegistryCredentials].
+	RegistryAuth string
+}
+
+// ImagePull pulls an image, like "docker pull", e.g. "nginx:alpine" or "ghcr.io/org/app@sha256:...".
+// The tag defaults to "latest".
+//
+// The credentials of the registry of the image are resolved from the Docker configuration,
+// unless set in the options. If the Docker daemon reports that they are not authorized,
+// they are resolved again, and the pull is retried once if they changed, e.g. because
+// a credential helper refreshed an expired token. Docker daemons report the registry
+// authentication failures as 401 Unauthorized, or as 404 Not Found and 500 Internal
+// Server Error with a "pull access denied" or "unauthorized" message.
+//
+// The returned stream reports the progress of the pull, and must be closed. The pull
+// has completed once the stream is read to its end without errors, e.g. with [MessageStream.Wait]
+// or [DisplayMessages].
+func (c *Client) ImagePull(ctx context.Context, ref string, opts PullOptions) (*MessageStream, error) {
+	image, err := parseImageReference(ref)
+	if err != nil {
+		return nil, fmt.Errorf("pull image: %w", err)
+	}
+
+	query := url.Values{"fromImage": {image.repository}}
+	if !opts.All {
+		query.Set("tag", image.tag)
+	}
+	if opts.Platform != "" {
+		query.Set("platform", opts.Platform)
+	}
+
+	auth := opts.RegistryAuth
+	if auth == "" {
+		if auth, err = registryAuth(image.registry); err != nil {
+			return nil, fmt.Errorf("pull image: %w", err)
+		}
+	}
+
+	resp, err := c.imageCreate(ctx, query, auth)
+	if isRegistryAuthError(err) && opts.RegistryAuth == "" {
+		fresh, authErr := registryAuth(image.registry)
+		if authErr != nil {
+			return nil, fmt.Errorf("pull image: %w", authErr)
+		}
+		if fresh != auth {
+			resp, err = c.imageCreate(ctx, query, fresh)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pull image: %w", err)
+	}
+
+	return NewMessageStream(resp.Body), nil
+}
+
+// isRegistryAuthError reports whether the error of a pull request is a registry authentication failure.
+func isRegistryAuthError(err error) bool {
+	if errors.Is(err, ErrUnauthorized) {
+		return true
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) ||
+		(apiErr.StatusCode != http.StatusNotFound && apiErr.StatusCode != http.StatusInternalServerError) {
+		return false
+	}
+
+	msg := strings.ToLower(apiErr.Message)
+	return strings.Contains(msg, "pull access denied") || strings.Contains(msg, "unauthorized")
+}
+
+// imageCreate sends the "POST /images/create" request, with the given encoded credentials, if any.
+func (c *Client) imageCreate(ctx context.Context, query url.Values, auth string) (*http.Response, error) {
+	req := request{method: http.MethodPost, path: "/images/create", query: query}
+	if auth != "" {
+		req.header = http.Header{registryAuthHeader: {auth}}
+	}
+
+	return c.send(ctx, req)
+}
+
+// EncodeRegistryAuth encodes the registry credentials for the X-Registry-Auth header,
+// as base64url encoded JSON.
+func EncodeRegistryAuth(auth dockerconfig.AuthConfig) (string, error) {
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return "", fmt.Errorf("encode registry auth: %w", err)
+	}
+
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// registryAuth returns the encoded credentials of the given registry host, or an
+// empty string if there are none.
+func registryAuth(registry string) (string, error) {
+	host := dockerconfig.ResolveRegistryHost(registry)
+
+	username, password, err := dockerconfig.GetRegistryCredentials(host)
+	if err != nil {
+		return "", fmt.Errorf("get credentials for %s: %w", registry, err)
+	}
+
+	if username == "" && password == "" {
+		return "", nil
+	}
+
+	auth := dockerconfig.AuthConfig{Username: username, Password: password, ServerAddress: host}
+	if username == "" {
+		// The password is an identity token.
+		auth = dockerconfig.AuthConfig{IdentityToken: password, ServerAddress: host}
+	}
+
+	return EncodeRegistryAuth(auth)
+}
+
+// imageReference is a parsed image reference
+type imageReference struct {
+	// registry is the registry host, e.g. "docker.io" or "localhost:5000"
+	registry string
+
+	// repository is the repository, as written in the reference, e.g. "nginx" or "ghcr.io/org/app"
+	repository string
+
+	// tag is the tag or the digest of the image
+	tag string
+}
+
+// parseImageReference parses an image reference, following the rules of the Docker CLI:
+// the first component of the repository is the registry host if it contains a "." or
+// a ":", or is "localhost". Otherwise, the registry is Docker Hub. The digest takes
+// precedence over the tag, which defaults to "latest".
+func parseImageReference(ref string) (imageReference, error) {
+	repository, digest, hasDigest := strings.Cut(ref, "@")
+
+	var tag string
+	hasTag := false
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tag, hasTag = repository[:i], repository[i+1:], true
+	}
+
+	if repository == "" || (hasTag && tag == "") || (hasDigest && digest == "") {
+		return imageReference{}, fmt.Errorf("%w: invalid reference format %q", ErrInvalidParameter, ref)
+	}
+
+	switch {
+	case digest != "":
+		tag = digest
+	case tag == "":
+		tag = defaultTag
+	}
+
+	registry := defaultRegistry
+	if first, _, found := strings.Cut(repository, "/"); found &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry = first
+	}
+
+	return imageReference{registry: registry, repository: repository, tag: tag}, nil
+}
@@ -0,0 +1,326 @@
+package dockerclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+)
+
+func TestClient_ImagePull(t *testing.T) {
+	t.Run("anonymous", func(tt *testing.T) {
+		setupDockerConfig(tt, `{"credHelpers":{"https://index.docker.io/v1/":"missing"}}`)
+
+		registry := &fakeRegistry{}
+		cli := newPullClient(tt, registry)
+
+		stream, err := cli.ImagePull(context.Background(), "nginx", PullOptions{Platform: "linux/arm64"})
+		require.NoError(tt, err)
+		require.Equal(tt, []string{"Pulling from library/nginx", "Downloading", "Pull complete", "Status: Downloaded newer image for nginx:latest"}, readStatuses(tt, stream))
+
+		require.Len(tt, registry.requests, 1)
+		require.Equal(tt, "fromImage=nginx&platform=linux%2Farm64&tag=latest", registry.requests[0].query)
+		require.Empty(tt, registry.requests[0].auth)
+	})
+
+	t.Run("credentials", func(tt *testing.T) {
+		setupDockerConfig(tt, `{"auths":{"localhost:5000":{"username":"user","password":"secret"}}}`)
+
+		registry := &fakeRegistry{password: "secret"}
+		cli := newPullClient(tt, registry)
+
+		stream, err := cli.ImagePull(context.Background(), "localhost:5000/app:1.0", PullOptions{})
+		require.NoError(tt, err)
+		require.NotEmpty(tt, readStatuses(tt, stream))
+
+		require.Len(tt, registry.requests, 1)
+		require.Equal(tt, "fromImage=localhost%3A5000%2Fapp&tag=1.0", registry.requests[0].query)
+		require.Equal(tt, dockerconfig.AuthConfig{Username: "user", Password: "secret", ServerAddress: "localhost:5000"}, registry.requests[0].auth)
+	})
+
+	t.Run("identity-token", func(tt *testing.T) {
+		setupDockerConfig(tt, `{"auths":{"https://index.docker.io/v1/":{"identitytoken":"token"}}}`)
+
+		registry := &fakeRegistry{}
+		cli := newPullClient(tt, registry)
+
+		stream, err := cli.ImagePull(context.Background(), "org/app", PullOptions{})
+		require.NoError(tt, err)
+		require.NoError(tt, stream.Close())
+
+		require.Len(tt, registry.requests, 1)
+		require.Equal(tt, dockerconfig.AuthConfig{IdentityToken: "token", ServerAddress: "https://index.docker.io/v1/"}, registry.requests[0].auth)
+	})
+
+	t.Run("unauthorized/refreshed", func(tt *testing.T) {
+		configDir := setupDockerConfig(tt, `{"auths":{"localhost:5000":{"username":"user","password":"expired"}}}`)
+
+		registry := &fakeRegistry{
+			password: "refreshed",
+			unauthorized: func() {
+				// the credentials are refreshed while the pull is rejected
+				writeDockerConfig(tt, configDir, `{"auths":{"localhost:5000":{"username":"user","password":"refreshed"}}}`)
+			},
+		}
+		cli := newPullClient(tt, registry)
+
+		stream, err := cli.ImagePull(context.Background(), "localhost:5000/app", PullOptions{})
+		require.NoError(tt, err)
+		require.NotEmpty(tt, readStatuses(tt, stream))
+
+		require.Len(tt, registry.requests, 2)
+		require.Equal(tt, "expired", registry.requests[0].auth.Password)
+		require.Equal(tt, "refreshed", registry.requests[1].auth.Password)
+	})
+
+	for _, rejection := range []struct {
+		name    string
+		status  int
+		message string
+	}{
+		{name: "pull-access-denied", status: http.StatusNotFound, message: "pull access denied for localhost:5000/app, repository does not exist or may require 'docker login': denied: requested access to the resource is denied"},
+		{name: "server-error", status: http.StatusInternalServerError, message: "Head \"https://localhost:5000/v2/app/manifests/latest\": unauthorized: authentication required"},
+	} {
+		t.Run("unauthorized/"+rejection.name, func(tt *testing.T) {
+			configDir := setupDockerConfig(tt, `{"auths":{"localhost:5000":{"username":"user","password":"expired"}}}`)
+
+			registry := &fakeRegistry{
+				password:      "refreshed",
+				rejectStatus:  rejection.status,
+				rejectMessage: rejection.message,
+				unauthorized: func() {
+					writeDockerConfig(tt, configDir, `{"auths":{"localhost:5000":{"username":"user","password":"refreshed"}}}`)
+				},
+			}
+			cli := newPullClient(tt, registry)
+
+			stream, err := cli.ImagePull(context.Background(), "localhost:5000/app", PullOptions{})
+			require.NoError(tt, err)
+			require.NotEmpty(tt, readStatuses(tt, stream))
+			require.Len(tt, registry.requests, 2)
+		})
+	}
+
+	t.Run("not-found", func(tt *testing.T) {
+		setupDockerConfig(tt, `{"auths":{"localhost:5000":{"username":"user","password":"expired"}}}`)
+
+		registry := &fakeRegistry{
+			password:      "refreshed",
+			rejectStatus:  http.StatusNotFound,
+			rejectMessage: "manifest for localhost:5000/app:latest not found: manifest unknown",
+		}
+		cli := newPullClient(tt, registry)
+
+		_, err := cli.ImagePull(context.Background(), "localhost:5000/app", PullOptions{})
+		require.ErrorIs(tt, err, ErrNotFound)
+		require.Len(tt, registry.requests, 1) // not an authentication failure
+	})
+
+	t.Run("unauthorized/unchanged", func(tt *testing.T) {
+		setupDockerConfig(tt, `{"auths":{"localhost:5000":{"username":"user","password":"wrong"}}}`)
+
+		registry := &fakeRegistry{password: "secret"}
+		cli := newPullClient(tt, registry)
+
+		_, err := cli.ImagePull(context.Background(), "localhost:5000/app", PullOptions{})
+		require.ErrorIs(tt, err, ErrUnauthorized)
+		require.Len(tt, registry.requests, 1) // no retry with the same credentials
+	})
+
+	t.Run("registry-auth", func(tt *testing.T) {
+		setupDockerConfig(tt, `{"auths":{"localhost:5000":{"username":"user","password":"wrong"}}}`)
+
+		auth, err := EncodeRegistryAuth(dockerconfig.AuthConfig{Username: "other", Password: "secret"})
+		require.NoError(tt, err)
+
+		registry := &fakeRegistry{password: "secret"}
+		cli := newPullClient(tt, registry)
+
+		stream, err := cli.ImagePull(context.Background(), "localhost:5000/app@sha256:abc", PullOptions{RegistryAuth: auth})
+		require.NoError(tt, err)
+		require.NoError(tt, stream.Close())
+
+		require.Len(tt, registry.requests, 1)
+		require.Equal(tt, "fromImage=localhost%3A5000%2Fapp&tag=sha256%3Aabc", registry.requests[0].query)
+		require.Equal(tt, "other", registry.requests[0].auth.Username)
+	})
+
+	t.Run("all-tags", func(tt *testing.T) {
+		setupDockerConfig(tt, `{"credHelpers":{"https://index.docker.io/v1/":"missing"}}`)
+
+		registry := &fakeRegistry{}
+		cli := newPullClient(tt, registry)
+
+		stream, err := cli.ImagePull(context.Background(), "nginx:alpine", PullOptions{All: true})
+		require.NoError(tt, err)
+		require.NoError(tt, stream.Close())
+		require.Equal(tt, "fromImage=nginx", registry.requests[0].query)
+	})
+
+	t.Run("invalid-reference", func(tt *testing.T) {
+		cli := newPullClient(tt, &fakeRegistry{})
+
+		_, err := cli.ImagePull(context.Background(), "nginx:", PullOptions{})
+		require.ErrorIs(tt, err, ErrInvalidParameter)
+	})
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected imageReference
+	}{
+		{ref: "nginx", expected: imageReference{registry: "docker.io", repository: "nginx", tag: "latest"}},
+		{ref: "nginx:alpine", expected: imageReference{registry: "docker.io", repository: "nginx", tag: "alpine"}},
+		{ref: "org/app:1.0", expected: imageReference{registry: "docker.io", repository: "org/app", tag: "1.0"}},
+		{ref: "docker.io/library/nginx", expected: imageReference{registry: "docker.io", repository: "docker.io/library/nginx", tag: "latest"}},
+		{ref: "ghcr.io/org/app", expected: imageReference{registry: "ghcr.io", repository: "ghcr.io/org/app", tag: "latest"}},
+		{ref: "localhost/app", expected: imageReference{registry: "localhost", repository: "localhost/app", tag: "latest"}},
+		{ref: "localhost:5000/app:1.0", expected: imageReference{registry: "localhost:5000", repository: "localhost:5000/app", tag: "1.0"}},
+		{ref: "app@sha256:abc", expected: imageReference{registry: "docker.io", repository: "app", tag: "sha256:abc"}},
+		{ref: "app:1.0@sha256:abc", expected: imageReference{registry: "docker.io", repository: "app", tag: "sha256:abc"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.ref, func(tt *testing.T) {
+			image, err := parseImageReference(tc.ref)
+			require.NoError(tt, err)
+			require.Equal(tt, tc.expected, image)
+		})
+	}
+
+	for _, ref := range []string{"", ":latest", "nginx:", "nginx@", "@sha256:abc"} {
+		t.Run("invalid/"+ref, func(tt *testing.T) {
+			_, err := parseImageReference(ref)
+			require.ErrorIs(tt, err, ErrInvalidParameter)
+		})
+	}
+}
+
+// fakeRegistry serves the pulls of the fake Docker daemon, accepting the credentials
+// with the given password, or any credentials if empty.
+type fakeRegistry struct {
+	password string
+
+	// rejectStatus and rejectMessage are the error of the rejected credentials,
+	// 401 Unauthorized if not set
+	rejectStatus  int
+	rejectMessage string
+
+	// unauthorized is called when the credentials are rejected, if not nil
+	unauthorized func()
+
+	mu       sync.Mutex
+	requests []pullRequest
+}
+
+// pullRequest is a pull request received by the fake registry
+type pullRequest struct {
+	query string
+	auth  dockerconfig.AuthConfig
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var auth dockerconfig.AuthConfig
+	if header := req.Header.Get("X-Registry-Auth"); header != "" {
+		data, err := base64.URLEncoding.DecodeString(header)
+		if err != nil {
+			errorHandler(http.StatusBadRequest, err.Error())(w, req)
+			return
+		}
+		if err := json.Unmarshal(data, &auth); err != nil {
+			errorHandler(http.StatusBadRequest, err.Error())(w, req)
+			return
+		}
+	}
+
+	r.mu.Lock()
+	r.requests = append(r.requests, pullRequest{query: req.URL.RawQuery, auth: auth})
+	r.mu.Unlock()
+
+	if r.password != "" && auth.Password != r.password {
+		if r.unauthorized != nil {
+			r.unauthorized()
+		}
+		status, message := r.rejectStatus, r.rejectMessage
+		if status == 0 {
+			status, message = http.StatusUnauthorized, "Head \"https://localhost:5000/v2/app/manifests/latest\": unauthorized"
+		}
+		errorHandler(status, message)(w, req)
+		return
+	}
+
+	repository := req.URL.Query().Get("fromImage")
+	if repository == "nginx" {
+		repository = "library/nginx"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	for _, msg := range []JSONMessage{
+		{Status: "Pulling from " + repository, ID: "latest"},
+		{Status: "Downloading", ID: "a1b2c3", Progress: &JSONProgress{Current: 512, Total: 1024}},
+		{Status: "Pull complete", ID: "a1b2c3"},
+		{Status: "Status: Downloaded newer image for " + req.URL.Query().Get("fromImage") + ":" + req.URL.Query().Get("tag")},
+	} {
+		if err := enc.Encode(msg); err != nil {
+			return
+		}
+	}
+}
+
+// newPullClient returns a client of a fake Docker daemon serving the pulls with the given registry.
+func newPullClient(t *testing.T, registry *fakeRegistry) *Client {
+	t.Helper()
+
+	mux := newDaemonMux(t, DefaultAPIVersion)
+	mux.Handle("POST /v"+DefaultAPIVersion+"/images/create", registry)
+
+	return newTestClient(t, serveDaemon(t, mux), "")
+}
+
+// setupDockerConfig writes the given config.json to a temporary directory, used as the
+// Docker configuration directory. It returns the directory.
+func setupDockerConfig(t *testing.T, configJSON string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeDockerConfig(t, dir, configJSON)
+	t.Setenv(dockerconfig.EnvOverrideDir, dir)
+	t.Setenv("DOCKER_AUTH_CONFIG", "")
+
+	return dir
+}
+
+// writeDockerConfig writes the given config.json to the given directory.
+func writeDockerConfig(t *testing.T, dir, configJSON string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, dockerconfig.FileName), []byte(configJSON), 0o600))
+}
+
+// readStatuses reads the stream to its end, returning the statuses of its messages.
+func readStatuses(t *testing.T, stream *MessageStream) []string {
+	t.Helper()
+	defer stream.Close()
+
+	var statuses []string
+	for {
+		msg, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			return statuses
+		}
+		require.NoError(t, err)
+		statuses = append(statuses, msg.Status)
+	}
+}
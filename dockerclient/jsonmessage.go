@@ -0,0 +1,118 @@
+package dockerclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// JSONMessage is a message of the newline-delimited JSON progress streams
+// of the Docker daemon, e.g. of an image pull.
+type JSONMessage struct {
+	// Stream is the output of a build step.
+	Stream string `json:"stream,omitempty"`
+
+	// Status is the status of the operation, e.g. "Downloading" or "Pull complete".
+	Status string `json:"status,omitempty"`
+
+	// ID is the ID of the object the status refers to, e.g. an image layer.
+	ID string `json:"id,omitempty"`
+
+	// Progress is the progress of the operation on the object, if any.
+	Progress *JSONProgress `json:"progressDetail,omitempty"`
+
+	// ProgressMessage is the progress rendered as text by the Docker daemon, e.g. a progress bar.
+	ProgressMessage string `json:"progress,omitempty"`
+
+	// Error is the error that aborted the operation, if any.
+	Error *JSONError `json:"errorDetail,omitempty"`
+
+	// ErrorMessage is the message of the error, kept for compatibility with older Docker daemons.
+	// Use Error instead, which is set from it if missing.
+	ErrorMessage string `json:"error,omitempty"`
+
+	// Aux is the auxiliary data of the operation, e.g. the ID of a built image
+	// or the digest of a pushed one, to be decoded into the type expected by the caller.
+	Aux json.RawMessage `json:"aux,omitempty"`
+}
+
+// JSONProgress is the progress of an operation, e.g. of the download of an image layer.
+type JSONProgress struct {
+	// Current is the number of units done, e.g. bytes.
+	Current int64 `json:"current,omitempty"`
+
+	// Total is the total number of units, or zero if unknown.
+	Total int64 `json:"total,omitempty"`
+}
+
+// JSONError is the error of a progress stream.
+type JSONError struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *JSONError) Error() string {
+	return e.Message
+}
+
+// MessageStream decodes the messages of a progress stream of the Docker daemon, such as
+// the responses of the pull, push, build, load and import requests, keeping their
+// aggregated progress. It must be closed to release the connection.
+type MessageStream struct {
+	body     io.ReadCloser
+	dec      *json.Decoder
+	progress progressTracker
+}
+
+// NewMessageStream returns a stream decoding the newline-delimited JSON messages of the
+// given response body, e.g. of a request to the Docker daemon not covered by the [Client].
+func NewMessageStream(body io.ReadCloser) *MessageStream {
+	return &MessageStream{body: body, dec: json.NewDecoder(body)}
+}
+
+// Next returns the next message of the stream, or [io.EOF] at its end.
+// If the message reports an error, it's returned along with the message, as a [*JSONError].
+func (s *MessageStream) Next() (JSONMessage, error) {
+	var msg JSONMessage
+	if err := s.dec.Decode(&msg); err != nil {
+		if errors.Is(err, io.EOF) {
+			return JSONMessage{}, io.EOF
+		}
+		return JSONMessage{}, fmt.Errorf("decode message: %w", err)
+	}
+
+	if msg.Error == nil && msg.ErrorMessage != "" {
+		msg.Error = &JSONError{Message: msg.ErrorMessage}
+	}
+	if msg.Error != nil {
+		return msg, msg.Error
+	}
+
+	s.progress.update(msg)
+
+	return msg, nil
+}
+
+// Wait reads the stream to its end, returning the first error it reports, if any.
+func (s *MessageStream) Wait() error {
+	for {
+		if _, err := s.Next(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Progress returns the aggregated progress of the messages read so far.
+func (s *MessageStream) Progress() Progress {
+	return s.progress.snapshot()
+}
+
+// Close closes the stream.
+func (s *MessageStream) Close() error {
+	return s.body.Close()
+}
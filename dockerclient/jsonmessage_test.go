@@ -0,0 +1,90 @@
+package dockerclient
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageStream_Next(t *testing.T) {
+	t.Run("messages", func(tt *testing.T) {
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"status":"Pulling fs layer","id":"a1b2c3"}
+{"status":"Downloading","progressDetail":{"current":10,"total":100},"id":"a1b2c3"}
+`)))
+		defer stream.Close()
+
+		msg, err := stream.Next()
+		require.NoError(tt, err)
+		require.Equal(tt, JSONMessage{Status: "Pulling fs layer", ID: "a1b2c3"}, msg)
+
+		msg, err = stream.Next()
+		require.NoError(tt, err)
+		require.Equal(tt, JSONMessage{Status: "Downloading", ID: "a1b2c3", Progress: &JSONProgress{Current: 10, Total: 100}}, msg)
+
+		_, err = stream.Next()
+		require.ErrorIs(tt, err, io.EOF)
+	})
+
+	t.Run("error", func(tt *testing.T) {
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}`)))
+		defer stream.Close()
+
+		msg, err := stream.Next()
+		var jsonErr *JSONError
+		require.ErrorAs(tt, err, &jsonErr)
+		require.EqualError(tt, err, "manifest unknown")
+		require.Equal(tt, jsonErr, msg.Error)
+	})
+
+	t.Run("legacy-error", func(tt *testing.T) {
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"error":"manifest unknown"}`)))
+		defer stream.Close()
+
+		_, err := stream.Next()
+		require.Equal(tt, &JSONError{Message: "manifest unknown"}, err)
+	})
+
+	t.Run("aux", func(tt *testing.T) {
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"aux":{"ID":"sha256:abc"}}`)))
+		defer stream.Close()
+
+		msg, err := stream.Next()
+		require.NoError(tt, err)
+
+		var aux struct{ ID string }
+		require.NoError(tt, json.Unmarshal(msg.Aux, &aux))
+		require.Equal(tt, "sha256:abc", aux.ID)
+	})
+
+	t.Run("invalid", func(tt *testing.T) {
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"status":`)))
+		defer stream.Close()
+
+		_, err := stream.Next()
+		require.ErrorContains(tt, err, "decode message")
+	})
+}
+
+func TestMessageStream_Wait(t *testing.T) {
+	t.Run("success", func(tt *testing.T) {
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"status":"Pulling fs layer","id":"a1b2c3"}
+{"status":"Pull complete","id":"a1b2c3"}
+`)))
+		defer stream.Close()
+
+		require.NoError(tt, stream.Wait())
+	})
+
+	t.Run("error", func(tt *testing.T) {
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"status":"Pulling fs layer","id":"a1b2c3"}
+{"errorDetail":{"code":1,"message":"unexpected EOF"}}
+`)))
+		defer stream.Close()
+
+		err := stream.Wait()
+		require.Equal(tt, &JSONError{Code: 1, Message: "unexpected EOF"}, err)
+	})
+}
@@ -0,0 +1,100 @@
+package dockerclient
+
+import (
+	"slices"
+	"strings"
+)
+
+// Progress is the aggregated progress of a stream of the Docker daemon, per layer and in total.
+type Progress struct {
+	// Layers is the progress of each layer, in the order they appeared in the stream.
+	Layers []LayerProgress
+
+	// Current is the number of bytes transferred, in all the layers.
+	Current int64
+
+	// Total is the number of bytes to transfer, in all the layers whose size is known.
+	Total int64
+}
+
+// LayerProgress is the progress of the transfer of a layer, e.g. its download on pull.
+type LayerProgress struct {
+	// ID is the ID of the layer.
+	ID string
+
+	// Status is the last status of the layer, e.g. "Downloading" or "Pull complete".
+	Status string
+
+	// Current is the number of bytes transferred.
+	Current int64
+
+	// Total is the size of the layer, or zero if unknown.
+	Total int64
+
+	// Complete reports whether the transfer is complete, which doesn't mean that the
+	// layer is, e.g. it may still be extracted.
+	Complete bool
+}
+
+// transferredStatuses are the statuses of the layers whose transfer is complete
+//
+//nolint:gochecknoglobals // Read-only set of the statuses reported by the Docker daemon.
+var transferredStatuses = map[string]bool{
+	"Already exists":       true,
+	"Download complete":    true,
+	"Verifying Checksum":   true,
+	"Extracting":           true,
+	"Pull complete":        true,
+	"Layer already exists": true,
+	"Pushed":               true,
+}
+
+// progressTracker aggregates the progress of the messages of a stream
+type progressTracker struct {
+	layers []LayerProgress
+	index  map[string]int
+}
+
+// update updates the progress with the given message. Messages without ID,
+// or reporting the repository being pulled, don't refer to a layer.
+func (p *progressTracker) update(msg JSONMessage) {
+	if msg.ID == "" || strings.HasPrefix(msg.Status, "Pulling from ") {
+		return
+	}
+
+	i, ok := p.index[msg.ID]
+	if !ok {
+		if p.index == nil {
+			p.index = map[string]int{}
+		}
+		i = len(p.layers)
+		p.index[msg.ID] = i
+		p.layers = append(p.layers, LayerProgress{ID: msg.ID})
+	}
+
+	layer := &p.layers[i]
+	layer.Status = msg.Status
+
+	// e.g. "Mounted from library/nginx" on push
+	if transferredStatuses[msg.Status] || strings.HasPrefix(msg.Status, "Mounted from ") {
+		layer.Complete = true
+		layer.Current = layer.Total
+		return
+	}
+
+	if msg.Progress != nil && !layer.Complete {
+		layer.Current = msg.Progress.Current
+		layer.Total = max(layer.Total, msg.Progress.Total)
+	}
+}
+
+// snapshot returns a copy of the progress, with its totals.
+func (p *progressTracker) snapshot() Progress {
+	progress := Progress{Layers: slices.Clone(p.layers)}
+	for _, layer := range p.layers {
+		progress.Current += layer.Current
+		progress.Total += layer.Total
+	}
+
+	return progress
+}
@@ -0,0 +1,69 @@
+package dockerclient
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageStream_Progress(t *testing.T) {
+	t.Run("pull", func(tt *testing.T) {
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"status":"Pulling from library/nginx","id":"latest"}
+{"status":"Already exists","id":"aaa"}
+{"status":"Pulling fs layer","id":"bbb"}
+{"status":"Pulling fs layer","id":"ccc"}
+{"status":"Downloading","progressDetail":{"current":300,"total":1000},"id":"bbb"}
+{"status":"Downloading","progressDetail":{"current":50,"total":200},"id":"ccc"}
+`)))
+		defer stream.Close()
+
+		require.NoError(tt, stream.Wait())
+		require.Equal(tt, Progress{
+			Layers: []LayerProgress{
+				{ID: "aaa", Status: "Already exists", Complete: true},
+				{ID: "bbb", Status: "Downloading", Current: 300, Total: 1000},
+				{ID: "ccc", Status: "Downloading", Current: 50, Total: 200},
+			},
+			Current: 350,
+			Total:   1200,
+		}, stream.Progress())
+	})
+
+	t.Run("complete", func(tt *testing.T) {
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"status":"Downloading","progressDetail":{"current":300,"total":1000},"id":"bbb"}
+{"status":"Download complete","id":"bbb"}
+{"status":"Extracting","progressDetail":{"current":10,"total":1000},"id":"bbb"}
+{"status":"Pushing","progressDetail":{"current":20,"total":100},"id":"ddd"}
+{"status":"Mounted from library/nginx","id":"ddd"}
+`)))
+		defer stream.Close()
+
+		require.NoError(tt, stream.Wait())
+		require.Equal(tt, Progress{
+			Layers: []LayerProgress{
+				{ID: "bbb", Status: "Extracting", Current: 1000, Total: 1000, Complete: true},
+				{ID: "ddd", Status: "Mounted from library/nginx", Current: 100, Total: 100, Complete: true},
+			},
+			Current: 1100,
+			Total:   1100,
+		}, stream.Progress())
+	})
+
+	t.Run("snapshot", func(tt *testing.T) {
+		stream := NewMessageStream(io.NopCloser(strings.NewReader(`{"status":"Downloading","progressDetail":{"current":1,"total":10},"id":"aaa"}
+{"status":"Downloading","progressDetail":{"current":5,"total":10},"id":"aaa"}
+`)))
+		defer stream.Close()
+
+		_, err := stream.Next()
+		require.NoError(tt, err)
+		progress := stream.Progress()
+
+		_, err = stream.Next()
+		require.NoError(tt, err)
+		require.Equal(tt, int64(1), progress.Layers[0].Current)
+		require.Equal(tt, int64(5), stream.Progress().Layers[0].Current)
+	})
+}
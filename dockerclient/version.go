@@ -0,0 +1,186 @@
+package dockerclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultAPIVersion is the maximum version of the Docker Engine API supported by the client.
+	// It's used when the Docker daemon supports a newer one.
+	DefaultAPIVersion = "1.47"
+
+	// fallbackAPIVersion is the API version of the Docker daemons not reporting it on ping,
+	// as the Docker client does
+	fallbackAPIVersion = "1.24"
+)
+
+// Ping is the information reported by the Docker daemon on ping.
+type Ping struct {
+	// APIVersion is the maximum API version supported by the Docker daemon.
+	APIVersion string
+
+	// OSType is the operating system of the Docker daemon, e.g. "linux".
+	OSType string
+
+	// Experimental reports whether the Docker daemon has the experimental features enabled.
+	Experimental bool
+
+	// BuilderVersion is the default builder of the Docker daemon, e.g. "2" for BuildKit.
+	BuilderVersion string
+}
+
+// Ping pings the Docker daemon, with the unversioned "GET /_ping" request.
+func (c *Client) Ping(ctx context.Context) (Ping, error) {
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/_ping", unversioned: true})
+	if err != nil {
+		return Ping{}, fmt.Errorf("ping: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return Ping{
+		APIVersion:     resp.Header.Get("Api-Version"),
+		OSType:         resp.Header.Get("Ostype"),
+		Experimental:   resp.Header.Get("Docker-Experimental") == "true",
+		BuilderVersion: resp.Header.Get("Builder-Version"),
+	}, nil
+}
+
+// Version is the version information of the Docker daemon.
+type Version struct {
+	Version       string `json:"Version"`
+	APIVersion    string `json:"ApiVersion"`
+	MinAPIVersion string `json:"MinAPIVersion,omitempty"`
+	GitCommit     string `json:"GitCommit"`
+	GoVersion     string `json:"GoVersion"`
+	Os            string `json:"Os"`
+	Arch          string `json:"Arch"`
+	KernelVersion string `json:"KernelVersion,omitempty"`
+	BuildTime     string `json:"BuildTime,omitempty"`
+}
+
+// ServerVersion returns the version information of the Docker daemon, like "docker version".
+func (c *Client) ServerVersion(ctx context.Context) (Version, error) {
+	resp, err := c.get(ctx, "/version", nil)
+	if err != nil {
+		return Version{}, fmt.Errorf("server version: %w", err)
+	}
+
+	var v Version
+	if err := decodeJSON(resp, &v); err != nil {
+		return Version{}, fmt.Errorf("server version: %w", err)
+	}
+
+	return v, nil
+}
+
+// ClientVersion returns the API version used in the requests: the pinned one, the negotiated
+// one, or [DefaultAPIVersion] if it's not negotiated yet.
+func (c *Client) ClientVersion() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version == "" {
+		return DefaultAPIVersion
+	}
+	return c.version
+}
+
+// NegotiateAPIVersion pings the Docker daemon, and uses the lowest of its maximum API version
+// and [DefaultAPIVersion] in the following requests. Daemons not reporting their API version
+// are assumed to support version 1.24. It does nothing if the API version is pinned.
+//
+// It's called on the first request, so calling it is only needed to detect the API version
+// in advance. If it fails, it's called again on the next request.
+func (c *Client) NegotiateAPIVersion(ctx context.Context) error {
+	_, err := c.negotiatedVersion(ctx)
+	return err
+}
+
+// negotiatedVersion returns the API version of the requests, negotiating it if needed.
+// Concurrent callers share a single ping, which is sent without holding the lock, and
+// ping again if it fails.
+func (c *Client) negotiatedVersion(ctx context.Context) (string, error) {
+	for {
+		c.mu.Lock()
+		if c.negotiated {
+			version := c.version
+			c.mu.Unlock()
+			return version, nil
+		}
+
+		if c.negotiating == nil {
+			done := make(chan struct{})
+			c.negotiating = done
+			c.mu.Unlock()
+
+			version, err := c.negotiate(ctx)
+
+			c.mu.Lock()
+			if err == nil {
+				c.version = version
+				c.negotiated = true
+			}
+			c.negotiating = nil
+			c.mu.Unlock()
+			close(done)
+
+			return version, err
+		}
+
+		negotiating := c.negotiating
+		c.mu.Unlock()
+
+		select {
+		case <-negotiating:
+		case <-ctx.Done():
+			return "", fmt.Errorf("negotiate api version: %w", ctx.Err())
+		}
+	}
+}
+
+// negotiate pings the Docker daemon, returning the API version to use with it.
+func (c *Client) negotiate(ctx context.Context) (string, error) {
+	ping, err := c.Ping(ctx)
+	if err != nil {
+		return "", fmt.Errorf("negotiate api version: %w", err)
+	}
+
+	version := ping.APIVersion
+	if version == "" {
+		version = fallbackAPIVersion
+	}
+	if compareVersions(version, DefaultAPIVersion) > 0 {
+		version = DefaultAPIVersion
+	}
+
+	return version, nil
+}
+
+// compareVersions compares two API versions, e.g. "1.47" and "1.9", returning
+// -1, 0 or 1 if the first one is lower, equal or greater than the second one.
+// Missing or invalid parts are compared as zero.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,158 @@
+package dockerclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_NegotiateAPIVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		daemonVersion string
+		pinned        string
+		want          string
+	}{
+		{name: "older-daemon", daemonVersion: "1.45", want: "1.45"},
+		{name: "same-version", daemonVersion: DefaultAPIVersion, want: DefaultAPIVersion},
+		{name: "newer-daemon", daemonVersion: "1.50", want: DefaultAPIVersion},
+		{name: "no-version", want: fallbackAPIVersion},
+		{name: "pinned", daemonVersion: "1.45", pinned: "1.41", want: "1.41"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tt *testing.T) {
+			var pings atomic.Int32
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /_ping", func(w http.ResponseWriter, _ *http.Request) {
+				pings.Add(1)
+				if tc.daemonVersion != "" {
+					w.Header().Set("Api-Version", tc.daemonVersion)
+				}
+			})
+			mux.HandleFunc("GET /v"+tc.want+"/version", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = io.WriteString(w, `{"Version":"27.3.1","ApiVersion":"`+tc.daemonVersion+`","Os":"linux","Arch":"amd64"}`)
+			})
+
+			cli := newTestClient(tt, serveDaemon(tt, mux), tc.pinned)
+
+			v, err := cli.ServerVersion(context.Background())
+			require.NoError(tt, err)
+			require.Equal(tt, "27.3.1", v.Version)
+			require.Equal(tt, tc.want, cli.ClientVersion())
+
+			_, err = cli.ServerVersion(context.Background())
+			require.NoError(tt, err)
+
+			if tc.pinned != "" {
+				require.Zero(tt, pings.Load())
+			} else {
+				require.Equal(tt, int32(1), pings.Load()) // negotiated once
+			}
+		})
+	}
+
+	t.Run("retry", func(tt *testing.T) {
+		var fail atomic.Bool
+		fail.Store(true)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /_ping", func(w http.ResponseWriter, _ *http.Request) {
+			if fail.Load() {
+				errorHandler(http.StatusServiceUnavailable, "starting")(w, nil)
+				return
+			}
+			w.Header().Set("Api-Version", "1.45")
+		})
+
+		cli := newTestClient(tt, serveDaemon(tt, mux), "")
+
+		err := cli.NegotiateAPIVersion(context.Background())
+		require.ErrorIs(tt, err, ErrUnavailable)
+		require.Equal(tt, DefaultAPIVersion, cli.ClientVersion())
+
+		fail.Store(false)
+		require.NoError(tt, cli.NegotiateAPIVersion(context.Background()))
+		require.Equal(tt, "1.45", cli.ClientVersion())
+	})
+
+	t.Run("concurrent", func(tt *testing.T) {
+		var pings atomic.Int32
+		pinged := make(chan struct{})
+		release := make(chan struct{})
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /_ping", func(w http.ResponseWriter, _ *http.Request) {
+			if pings.Add(1) == 1 {
+				close(pinged)
+			}
+			<-release
+			w.Header().Set("Api-Version", "1.45")
+		})
+
+		cli := newTestClient(tt, serveDaemon(tt, mux), "")
+
+		const callers = 5
+		errs := make(chan error, callers)
+		for range callers {
+			go func() { errs <- cli.NegotiateAPIVersion(context.Background()) }()
+		}
+		<-pinged
+
+		// the lock is not held during the ping
+		require.Equal(tt, DefaultAPIVersion, cli.ClientVersion())
+
+		// a caller whose context is done stops waiting for the ping in flight
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(tt, cli.NegotiateAPIVersion(ctx), context.Canceled)
+
+		close(release)
+		for range callers {
+			require.NoError(tt, <-errs)
+		}
+		require.Equal(tt, "1.45", cli.ClientVersion())
+		require.Equal(tt, int32(1), pings.Load()) // a single ping for all the callers
+	})
+}
+
+func TestClient_Ping(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /_ping", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Api-Version", "1.47")
+		w.Header().Set("Ostype", "linux")
+		w.Header().Set("Docker-Experimental", "true")
+		w.Header().Set("Builder-Version", "2")
+		_, _ = io.WriteString(w, "OK")
+	})
+
+	cli := newTestClient(t, serveDaemon(t, mux), "")
+
+	ping, err := cli.Ping(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, Ping{APIVersion: "1.47", OSType: "linux", Experimental: true, BuilderVersion: "2"}, ping)
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.47", b: "1.47", want: 0},
+		{a: "1.9", b: "1.47", want: -1},
+		{a: "1.47", b: "1.9", want: 1},
+		{a: "2.0", b: "1.47", want: 1},
+		{a: "1.47", b: "1.47.0", want: 0},
+		{a: "1.47.1", b: "1.47", want: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"/"+tc.b, func(tt *testing.T) {
+			require.Equal(tt, tc.want, compareVersions(tc.a, tc.b))
+		})
+	}
+}
@@ -19,9 +19,14 @@ const tokenUsername = "<token>"
 
 // GetRegistryCredentials gets registry credentials for the passed in registry host.
 //
-// This will use [Load] to read registry auth details from the config.
+// Credentials registered with [RegisterEnvCredentials] are checked first.
+// Then it will use [Load] to read registry auth details from the config.
 // If the config doesn't exist, it will attempt to load registry credentials using the default credential helper for the platform.
 func GetRegistryCredentials(hostname string) (string, string, error) {
+	if username, password, ok := GetCredentialsFromEnv(hostname); ok {
+		return username, password, nil
+	}
+
 	cfg, err := Load()
 	if err != nil {
 		if !errors.Is(err, fs.ErrNotExist) {
@@ -50,9 +55,12 @@ func ResolveRegistryHost(host string) string {
 //
 // Hostnames should already be resolved using [ResolveRegistryHost].
 //
+// Keys in "credHelpers", and hosts registered with [RegisterCredentialHelper],
+// can be glob patterns, which are matched after the exact keys.
+//
 // If the returned username string is empty, the password is an identity token.
 func (c *Config) GetRegistryCredentials(hostname string) (string, string, error) {
-	h, ok := c.CredentialHelpers[hostname]
+	h, ok := c.credentialHelper(hostname)
 	if ok {
 		return GetCredentialsFromHelper(h, hostname)
 	}
@@ -194,6 +202,57 @@ func GetCredentialsFromHelper(helper, hostname string) (string, string, error) {
 	return creds.Username, creds.Secret, nil
 }
 
+// StoreCredentialsInHelper stores the credentials for the passed in registry host
+// in the passed in docker credential helper.
+//
+// The credential helper should just be the suffix name (no "docker-credential-").
+//
+// Hostnames should already be resolved using [ResolveRegistryHost]
+//
+// If the username is empty, the secret is stored as an identity token.
+func StoreCredentialsInHelper(helper, hostname, username, secret string) error {
+	if helper == "" {
+		return errors.New("credential helper not set")
+	}
+
+	helper = "docker-credential-" + helper
+	p, err := execLookPath(helper)
+	if err != nil {
+		return fmt.Errorf("look up %q: %w", helper, err)
+	}
+
+	if username == "" {
+		username = tokenUsername
+	}
+
+	input, err := json.Marshal(struct {
+		ServerURL string `json:"ServerURL"`
+		Username  string `json:"Username"`
+		Secret    string `json:"Secret"`
+	}{
+		ServerURL: hostname,
+		Username:  username,
+		Secret:    secret,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal credentials for: %q: %w", helper, err)
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	cmd := execCommand(p, "store")
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("execute %q stdout: %q stderr: %q: %w",
+			helper, strings.TrimSpace(outBuf.String()), strings.TrimSpace(errBuf.String()), err,
+		)
+	}
+
+	return nil
+}
+
 // getCredentialHelper gets the default credential helper name for the current platform.
 func getCredentialHelper() (string, error) {
 	switch runtime.GOOS {
@@ -0,0 +1,90 @@
+package dockerconfig
+
+import (
+	"os"
+	"sync"
+)
+
+// EnvCredentials maps a registry host pattern to the environment variables
+// holding the credentials for that registry. It's meant for CI systems, which
+// usually inject credentials as plain environment variables instead of
+// a config.json file.
+type EnvCredentials struct {
+	// Host is the registry host the credentials apply to. It can be a glob
+	// pattern, as understood by [path.Match], e.g. "*.example.com".
+	Host string
+
+	// UsernameEnv is the name of the environment variable holding the username.
+	UsernameEnv string
+
+	// PasswordEnv is the name of the environment variable holding the password.
+	PasswordEnv string
+
+	// IdentityTokenEnv is the name of the environment variable holding an
+	// identity token. When set to a non-empty value, it takes precedence over
+	// the username and password.
+	IdentityTokenEnv string
+}
+
+// lookup returns the credentials for the given hostname, if the host pattern matches
+// and the referenced environment variables are set.
+func (e EnvCredentials) lookup(hostname string) (string, string, bool) {
+	if !matchHost(e.Host, hostname) {
+		return "", "", false
+	}
+
+	if e.IdentityTokenEnv != "" {
+		if token := os.Getenv(e.IdentityTokenEnv); token != "" {
+			return "", token, true
+		}
+	}
+
+	if e.UsernameEnv == "" || e.PasswordEnv == "" {
+		return "", "", false
+	}
+
+	username, password := os.Getenv(e.UsernameEnv), os.Getenv(e.PasswordEnv)
+	if username == "" || password == "" {
+		return "", "", false
+	}
+
+	return username, password, true
+}
+
+//nolint:gochecknoglobals // Registered env credentials are process wide, like the env vars they read.
+var (
+	envCredentialsMu sync.RWMutex
+	envCredentials   []EnvCredentials
+)
+
+// RegisterEnvCredentials registers environment variable based credentials,
+// which are checked by [GetRegistryCredentials] ahead of the config file sources.
+//
+// Credentials are checked in registration order, and the first entry whose host
+// pattern matches and whose environment variables are set wins.
+func RegisterEnvCredentials(creds ...EnvCredentials) {
+	envCredentialsMu.Lock()
+	defer envCredentialsMu.Unlock()
+
+	envCredentials = append(envCredentials, creds...)
+}
+
+// GetCredentialsFromEnv gets credentials for the passed in registry host from the
+// environment variables registered with [RegisterEnvCredentials].
+//
+// Hostnames should already be resolved using [ResolveRegistryHost].
+//
+// The returned boolean reports whether credentials were found. If the returned
+// username string is empty, the password is an identity token.
+func GetCredentialsFromEnv(hostname string) (string, string, bool) {
+	envCredentialsMu.RLock()
+	defer envCredentialsMu.RUnlock()
+
+	for _, creds := range envCredentials {
+		if username, password, ok := creds.lookup(hostname); ok {
+			return username, password, true
+		}
+	}
+
+	return "", "", false
+}
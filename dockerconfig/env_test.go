@@ -0,0 +1,114 @@
+package dockerconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupEnvCredentials registers the given env credentials, restoring the previous ones on cleanup.
+func setupEnvCredentials(t *testing.T, creds ...EnvCredentials) {
+	t.Helper()
+
+	envCredentialsMu.Lock()
+	previous := envCredentials
+	envCredentials = nil
+	envCredentialsMu.Unlock()
+
+	RegisterEnvCredentials(creds...)
+
+	t.Cleanup(func() {
+		envCredentialsMu.Lock()
+		envCredentials = previous
+		envCredentialsMu.Unlock()
+	})
+}
+
+func TestGetCredentialsFromEnv(t *testing.T) {
+	t.Run("exact-host", func(t *testing.T) {
+		setupEnvCredentials(t, EnvCredentials{Host: "registry.io", UsernameEnv: "TEST_REGISTRY_USERNAME", PasswordEnv: "TEST_REGISTRY_PASSWORD"})
+		t.Setenv("TEST_REGISTRY_USERNAME", "user")
+		t.Setenv("TEST_REGISTRY_PASSWORD", "pass")
+
+		username, password, ok := GetCredentialsFromEnv("registry.io")
+		require.True(t, ok)
+		require.Equal(t, "user", username)
+		require.Equal(t, "pass", password)
+
+		_, _, ok = GetCredentialsFromEnv("other.io")
+		require.False(t, ok)
+	})
+
+	t.Run("glob-host", func(t *testing.T) {
+		setupEnvCredentials(t, EnvCredentials{Host: "*.registry.io", UsernameEnv: "TEST_REGISTRY_USERNAME", PasswordEnv: "TEST_REGISTRY_PASSWORD"})
+		t.Setenv("TEST_REGISTRY_USERNAME", "user")
+		t.Setenv("TEST_REGISTRY_PASSWORD", "pass")
+
+		username, password, ok := GetCredentialsFromEnv("eu.registry.io")
+		require.True(t, ok)
+		require.Equal(t, "user", username)
+		require.Equal(t, "pass", password)
+
+		_, _, ok = GetCredentialsFromEnv("registry.io")
+		require.False(t, ok)
+	})
+
+	t.Run("identity-token", func(t *testing.T) {
+		setupEnvCredentials(t, EnvCredentials{
+			Host:             "registry.io",
+			UsernameEnv:      "TEST_REGISTRY_USERNAME",
+			PasswordEnv:      "TEST_REGISTRY_PASSWORD",
+			IdentityTokenEnv: "TEST_REGISTRY_TOKEN",
+		})
+		t.Setenv("TEST_REGISTRY_USERNAME", "user")
+		t.Setenv("TEST_REGISTRY_PASSWORD", "pass")
+		t.Setenv("TEST_REGISTRY_TOKEN", "token")
+
+		username, password, ok := GetCredentialsFromEnv("registry.io")
+		require.True(t, ok)
+		require.Empty(t, username)
+		require.Equal(t, "token", password)
+	})
+
+	t.Run("unset-vars/falls-through", func(t *testing.T) {
+		setupEnvCredentials(t,
+			EnvCredentials{Host: "registry.io", UsernameEnv: "TEST_REGISTRY_USERNAME_UNSET", PasswordEnv: "TEST_REGISTRY_PASSWORD_UNSET"},
+			EnvCredentials{Host: "registry.io", UsernameEnv: "TEST_REGISTRY_USERNAME", PasswordEnv: "TEST_REGISTRY_PASSWORD"},
+		)
+		t.Setenv("TEST_REGISTRY_USERNAME", "user")
+		t.Setenv("TEST_REGISTRY_PASSWORD", "pass")
+
+		username, password, ok := GetCredentialsFromEnv("registry.io")
+		require.True(t, ok)
+		require.Equal(t, "user", username)
+		require.Equal(t, "pass", password)
+	})
+
+	t.Run("missing-password", func(t *testing.T) {
+		setupEnvCredentials(t, EnvCredentials{Host: "registry.io", UsernameEnv: "TEST_REGISTRY_USERNAME", PasswordEnv: "TEST_REGISTRY_PASSWORD"})
+		t.Setenv("TEST_REGISTRY_USERNAME", "user")
+		t.Setenv("TEST_REGISTRY_PASSWORD", "")
+
+		_, _, ok := GetCredentialsFromEnv("registry.io")
+		require.False(t, ok)
+	})
+}
+
+func TestGetRegistryCredentials_env(t *testing.T) {
+	t.Setenv(EnvOverrideDir, filepath.Join("testdata", "credhelpers-config"))
+
+	t.Run("env-ahead-of-config", func(t *testing.T) {
+		setupEnvCredentials(t, EnvCredentials{Host: "userpass.io", UsernameEnv: "TEST_REGISTRY_USERNAME", PasswordEnv: "TEST_REGISTRY_PASSWORD"})
+		t.Setenv("TEST_REGISTRY_USERNAME", "envuser")
+		t.Setenv("TEST_REGISTRY_PASSWORD", "envpass")
+
+		validateAuth(t, "userpass.io", "envuser", "envpass")
+	})
+
+	t.Run("env-unset/config", func(t *testing.T) {
+		setupEnvCredentials(t, EnvCredentials{Host: "userpass.io", UsernameEnv: "TEST_REGISTRY_USERNAME_UNSET", PasswordEnv: "TEST_REGISTRY_PASSWORD_UNSET"})
+
+		validateAuth(t, "userpass.io", "user", "pass")
+	})
+}
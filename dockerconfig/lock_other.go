@@ -0,0 +1,26 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package dockerconfig
+
+import (
+	"os"
+	"sync"
+)
+
+// fallbackLock serializes config updates within the process on platforms
+// without advisory file locks.
+//
+//nolint:gochecknoglobals // There is no per-file lock to hold on these platforms.
+var fallbackLock sync.Mutex
+
+// lockFD takes the process wide fallback lock.
+func lockFD(_ *os.File) error {
+	fallbackLock.Lock()
+	return nil
+}
+
+// unlockFD releases the process wide fallback lock.
+func unlockFD(_ *os.File) error {
+	fallbackLock.Unlock()
+	return nil
+}
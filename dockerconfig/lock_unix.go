@@ -0,0 +1,23 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package dockerconfig
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFD takes an exclusive flock on the file, blocking until it's acquired.
+func lockFD(f *os.File) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR { //nolint:errorlint // syscall errors are compared directly.
+			return err
+		}
+	}
+}
+
+// unlockFD releases the flock on the file.
+func unlockFD(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
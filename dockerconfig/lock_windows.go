@@ -0,0 +1,40 @@
+//go:build windows
+
+package dockerconfig
+
+import (
+	"math"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// lockfileExclusiveLock is the LOCKFILE_EXCLUSIVE_LOCK flag of LockFileEx.
+const lockfileExclusiveLock = 0x2
+
+//nolint:gochecknoglobals // Lazily loaded kernel32 procedures, to avoid depending on golang.org/x/sys.
+var (
+	modKernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = modKernel32.NewProc("LockFileEx")
+	procUnlockFileEx = modKernel32.NewProc("UnlockFileEx")
+)
+
+// lockFD takes an exclusive lock on the whole file, blocking until it's acquired.
+func lockFD(f *os.File) error {
+	var ol syscall.Overlapped
+	r1, _, err := procLockFileEx.Call(f.Fd(), lockfileExclusiveLock, 0, math.MaxUint32, math.MaxUint32, uintptr(unsafe.Pointer(&ol)))
+	if r1 == 0 {
+		return err
+	}
+	return nil
+}
+
+// unlockFD releases the lock on the file.
+func unlockFD(f *os.File) error {
+	var ol syscall.Overlapped
+	r1, _, err := procUnlockFileEx.Call(f.Fd(), 0, math.MaxUint32, math.MaxUint32, uintptr(unsafe.Pointer(&ol)))
+	if r1 == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package dockerconfig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrNoCredentialsStore is returned when migrating a plaintext auth entry
+// for a host without a configured credential store or helper.
+var ErrNoCredentialsStore = errors.New("no credentials store configured")
+
+// errDryRun is used to abort the config update when running a dry-run migration.
+var errDryRun = errors.New("dry run")
+
+// MigrationStatus is the outcome of migrating the plaintext auth entry of a host.
+type MigrationStatus string
+
+const (
+	// MigrationMigrated means the credentials were stored in the helper,
+	// and the plaintext copy was removed from the config.
+	MigrationMigrated MigrationStatus = "migrated"
+
+	// MigrationPending means the credentials would be migrated, when running in dry-run mode.
+	MigrationPending MigrationStatus = "pending"
+
+	// MigrationFailed means the credentials could not be migrated,
+	// so the plaintext copy was kept in the config.
+	MigrationFailed MigrationStatus = "failed"
+)
+
+// MigrationResult reports the migration of the plaintext auth entry of a host.
+type MigrationResult struct {
+	// Host is the key of the auth entry in the config.
+	Host string
+
+	// Helper is the credential helper the credentials are stored in,
+	// without the "docker-credential-" prefix.
+	Helper string
+
+	// Status is the outcome of the migration.
+	Status MigrationStatus
+
+	// Err is the reason of the failure, when Status is [MigrationFailed].
+	Err error
+}
+
+// MigrateOptions configures the migration of plaintext auth entries.
+type MigrateOptions struct {
+	// DryRun reports what would be migrated, without storing credentials
+	// in any helper or modifying the config.
+	DryRun bool
+}
+
+// MigrateAuths moves the plaintext auth entries of the config file into the configured
+// credential store or helpers. It uses [Update], so the config file is locked while
+// being migrated. See [Config.MigrateAuths] for details.
+func MigrateAuths(opts MigrateOptions) ([]MigrationResult, error) {
+	var results []MigrationResult
+	err := Update(func(cfg *Config) error {
+		results = cfg.MigrateAuths(opts)
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// MigrateAuths moves the plaintext auth entries of the config into the credential helper
+// configured for each host, or the credential store, using the helper's "store" command.
+// Each entry is confirmed with the helper's "get" command before its plaintext copy is
+// removed, keeping an empty entry for the host, as the docker CLI does.
+//
+// Entries without plaintext credentials are not reported. The results are sorted by host.
+// The config is modified in memory only, so the caller is responsible for saving it.
+func (c *Config) MigrateAuths(opts MigrateOptions) []MigrationResult {
+	hosts := make([]string, 0, len(c.AuthConfigs))
+	for host, auth := range c.AuthConfigs {
+		if hasPlaintextCredentials(auth) {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+
+	results := make([]MigrationResult, 0, len(hosts))
+	for _, host := range hosts {
+		result := MigrationResult{Host: host}
+		result.Helper, result.Err = c.migrateAuth(host, opts)
+
+		switch {
+		case result.Err != nil:
+			result.Status = MigrationFailed
+		case opts.DryRun:
+			result.Status = MigrationPending
+		default:
+			result.Status = MigrationMigrated
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// migrateAuth migrates the plaintext auth entry for the given host,
+// returning the credential helper the credentials are stored in.
+func (c *Config) migrateAuth(host string, opts MigrateOptions) (string, error) {
+	helper, ok := c.credentialHelper(host)
+	if !ok {
+		helper = c.CredentialsStore
+	}
+
+	if helper == "" {
+		return "", ErrNoCredentialsStore
+	}
+
+	username, secret, err := plaintextCredentials(c.AuthConfigs[host])
+	if err != nil {
+		return helper, err
+	}
+
+	if opts.DryRun {
+		return helper, nil
+	}
+
+	if err := StoreCredentialsInHelper(helper, host, username, secret); err != nil {
+		return helper, fmt.Errorf("store credentials: %w", err)
+	}
+
+	gotUsername, gotSecret, err := GetCredentialsFromHelper(helper, host)
+	if err != nil {
+		return helper, fmt.Errorf("confirm credentials: %w", err)
+	}
+
+	if gotUsername != username || gotSecret != secret {
+		return helper, errors.New("confirm credentials: stored credentials do not match")
+	}
+
+	c.AuthConfigs[host] = AuthConfig{}
+
+	return helper, nil
+}
+
+// hasPlaintextCredentials reports whether the auth entry holds credentials.
+func hasPlaintextCredentials(auth AuthConfig) bool {
+	return auth.Auth != "" || auth.Password != "" || auth.IdentityToken != ""
+}
+
+// plaintextCredentials returns the credentials held by the auth entry.
+// If the returned username is empty, the secret is an identity token.
+func plaintextCredentials(auth AuthConfig) (string, string, error) {
+	if auth.IdentityToken != "" {
+		return "", auth.IdentityToken, nil
+	}
+
+	if auth.Username != "" && auth.Password != "" {
+		return auth.Username, auth.Password, nil
+	}
+
+	username, password, err := DecodeBase64Auth(auth)
+	if err != nil {
+		return "", "", err
+	}
+
+	if username == "" || password == "" {
+		return "", "", errors.New("incomplete credentials")
+	}
+
+	return username, password, nil
+}
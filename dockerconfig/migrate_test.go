@@ -0,0 +1,144 @@
+package dockerconfig
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_MigrateAuths(t *testing.T) {
+	newConfig := func() Config {
+		return Config{
+			CredentialsStore: "helper",
+			AuthConfigs: map[string]AuthConfig{
+				"auth.io":     {Auth: base64.StdEncoding.EncodeToString([]byte("user:pass"))},
+				"userpass.io": {Username: "user", Password: "pass"},
+				"stored.io":   {},
+			},
+		}
+	}
+
+	t.Run("migrated", func(t *testing.T) {
+		mockExecCommand(t, `HELPER_STDOUT={"Username":"user","Secret":"pass"}`)
+
+		cfg := newConfig()
+		results := cfg.MigrateAuths(MigrateOptions{})
+		require.Equal(t, []MigrationResult{
+			{Host: "auth.io", Helper: "helper", Status: MigrationMigrated},
+			{Host: "userpass.io", Helper: "helper", Status: MigrationMigrated},
+		}, results)
+
+		require.Equal(t, map[string]AuthConfig{
+			"auth.io":     {},
+			"userpass.io": {},
+			"stored.io":   {},
+		}, cfg.AuthConfigs)
+	})
+
+	t.Run("dry-run", func(t *testing.T) {
+		mockExecCommand(t, "HELPER_EXIT_CODE=1")
+
+		cfg := newConfig()
+		results := cfg.MigrateAuths(MigrateOptions{DryRun: true})
+		require.Equal(t, []MigrationResult{
+			{Host: "auth.io", Helper: "helper", Status: MigrationPending},
+			{Host: "userpass.io", Helper: "helper", Status: MigrationPending},
+		}, results)
+		require.Equal(t, newConfig(), cfg)
+	})
+
+	t.Run("confirm-mismatch", func(t *testing.T) {
+		mockExecCommand(t, `HELPER_STDOUT={"Username":"other","Secret":"secret"}`)
+
+		cfg := newConfig()
+		results := cfg.MigrateAuths(MigrateOptions{})
+		require.Len(t, results, 2)
+		for _, result := range results {
+			require.Equal(t, MigrationFailed, result.Status)
+			require.ErrorContains(t, result.Err, "stored credentials do not match")
+		}
+		require.Equal(t, newConfig(), cfg)
+	})
+
+	t.Run("store-error", func(t *testing.T) {
+		mockExecCommand(t, "HELPER_STDOUT=output", "HELPER_STDERR=my error", "HELPER_EXIT_CODE=10")
+
+		cfg := newConfig()
+		results := cfg.MigrateAuths(MigrateOptions{})
+		require.Len(t, results, 2)
+		require.Equal(t, MigrationFailed, results[0].Status)
+		require.EqualError(t, results[0].Err, `store credentials: execute "docker-credential-helper" stdout: "output" stderr: "my error": exit status 10`)
+		require.Equal(t, newConfig(), cfg)
+	})
+
+	t.Run("credential-helper", func(t *testing.T) {
+		mockExecCommand(t, `HELPER_STDOUT={"Username":"<token>","Secret":"token"}`)
+
+		cfg := Config{
+			CredentialHelpers: map[string]string{"token.io": "helper"},
+			AuthConfigs: map[string]AuthConfig{
+				"token.io": {IdentityToken: "token"},
+				"other.io": {Username: "user", Password: "pass"},
+			},
+		}
+		results := cfg.MigrateAuths(MigrateOptions{})
+		require.Len(t, results, 2)
+
+		require.Equal(t, "other.io", results[0].Host)
+		require.Equal(t, MigrationFailed, results[0].Status)
+		require.ErrorIs(t, results[0].Err, ErrNoCredentialsStore)
+
+		require.Equal(t, MigrationResult{Host: "token.io", Helper: "helper", Status: MigrationMigrated}, results[1])
+		require.Equal(t, AuthConfig{}, cfg.AuthConfigs["token.io"])
+	})
+
+	t.Run("invalid-auth", func(t *testing.T) {
+		mockExecCommand(t)
+
+		cfg := Config{
+			CredentialsStore: "helper",
+			AuthConfigs: map[string]AuthConfig{
+				"invalid.io": {Auth: "not base64"},
+			},
+		}
+		results := cfg.MigrateAuths(MigrateOptions{})
+		require.Len(t, results, 1)
+		require.Equal(t, MigrationFailed, results[0].Status)
+		require.ErrorContains(t, results[0].Err, "decode auth")
+	})
+}
+
+func TestMigrateAuths(t *testing.T) {
+	dir := t.TempDir()
+	setupDockerConfigs(t, dir)
+	t.Setenv("DOCKER_AUTH_CONFIG", "")
+
+	configPath := filepath.Join(dir, FileName)
+	writeConfigForTest(t, configPath, `{"auths":{"userpass.io":{"username":"user","password":"pass"}},"credsStore":"helper"}`)
+
+	t.Run("dry-run", func(t *testing.T) {
+		mockExecCommand(t, "HELPER_EXIT_CODE=1")
+
+		results, err := MigrateAuths(MigrateOptions{DryRun: true})
+		require.NoError(t, err)
+		require.Equal(t, []MigrationResult{{Host: "userpass.io", Helper: "helper", Status: MigrationPending}}, results)
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		require.Equal(t, "pass", cfg.AuthConfigs["userpass.io"].Password)
+	})
+
+	t.Run("migrated", func(t *testing.T) {
+		mockExecCommand(t, `HELPER_STDOUT={"Username":"user","Secret":"pass"}`)
+
+		results, err := MigrateAuths(MigrateOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []MigrationResult{{Host: "userpass.io", Helper: "helper", Status: MigrationMigrated}}, results)
+
+		raw := readRawConfigForTest(t, configPath)
+		require.JSONEq(t, `{"userpass.io":{}}`, string(raw["auths"]))
+		require.JSONEq(t, `"helper"`, string(raw["credsStore"]))
+	})
+}
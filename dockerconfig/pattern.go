@@ -0,0 +1,141 @@
+package dockerconfig
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// hostPatternChars are the characters that turn a registry host into a glob pattern.
+const hostPatternChars = "*?["
+
+//nolint:gochecknoglobals // Credential helper overrides are process wide, like the config file.
+var (
+	credHelperOverridesMu sync.RWMutex
+	credHelperOverrides   = map[string]string{}
+)
+
+// RegisterCredentialHelper registers a credential helper for the given registry host,
+// overriding the "credHelpers" section of the config file. The host can be a glob
+// pattern, as understood by [path.Match], e.g. "*.dkr.ecr.*.amazonaws.com".
+//
+// The helper should just be the suffix name (no "docker-credential-").
+// An empty helper removes a previously registered override.
+func RegisterCredentialHelper(host, helper string) {
+	credHelperOverridesMu.Lock()
+	defer credHelperOverridesMu.Unlock()
+
+	if helper == "" {
+		delete(credHelperOverrides, host)
+		return
+	}
+
+	credHelperOverrides[host] = helper
+}
+
+// credentialHelper returns the credential helper to use for the given hostname,
+// and whether one was found. Helpers are resolved in this particular order:
+//  1. exact match in the overrides registered with [RegisterCredentialHelper]
+//  2. exact match in the "credHelpers" section of the config
+//  3. glob pattern in the registered overrides
+//  4. glob pattern in the "credHelpers" section of the config
+//
+// When more than one pattern matches, the most specific one wins: the pattern with
+// the most literal (non-wildcard) characters, then the one with fewer wildcards,
+// and finally the lexically smallest, so the result is always deterministic.
+func (c *Config) credentialHelper(hostname string) (string, bool) {
+	credHelperOverridesMu.RLock()
+	defer credHelperOverridesMu.RUnlock()
+
+	if h, ok := credHelperOverrides[hostname]; ok {
+		return h, true
+	}
+
+	if h, ok := c.CredentialHelpers[hostname]; ok {
+		return h, true
+	}
+
+	if h, ok := matchHostPattern(credHelperOverrides, hostname); ok {
+		return h, true
+	}
+
+	return matchHostPattern(c.CredentialHelpers, hostname)
+}
+
+// matchHostPattern returns the value of the most specific glob pattern key in m
+// matching the hostname. Keys without wildcards are ignored.
+func matchHostPattern(m map[string]string, hostname string) (string, bool) {
+	var patterns []string
+	for key := range m {
+		if isHostPattern(key) && matchHost(key, hostname) {
+			patterns = append(patterns, key)
+		}
+	}
+
+	if len(patterns) == 0 {
+		return "", false
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		return morePatternSpecific(patterns[i], patterns[j])
+	})
+
+	return m[patterns[0]], true
+}
+
+// morePatternSpecific reports whether pattern a is more specific than pattern b.
+func morePatternSpecific(a, b string) bool {
+	literalsA, wildcardsA := patternWeight(a)
+	literalsB, wildcardsB := patternWeight(b)
+
+	if literalsA != literalsB {
+		return literalsA > literalsB
+	}
+
+	if wildcardsA != wildcardsB {
+		return wildcardsA < wildcardsB
+	}
+
+	return a < b
+}
+
+// patternWeight returns the number of literal characters and wildcards in a glob pattern.
+// Character classes count as a single wildcard.
+func patternWeight(pattern string) (int, int) {
+	var literals, wildcards int
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*', '?':
+			wildcards++
+		case '[':
+			wildcards++
+			if end := strings.IndexByte(pattern[i:], ']'); end > 0 {
+				i += end
+			}
+		case '\\':
+			literals++
+			i++
+		default:
+			literals++
+		}
+	}
+
+	return literals, wildcards
+}
+
+// isHostPattern reports whether the registry host is a glob pattern.
+func isHostPattern(host string) bool {
+	return strings.ContainsAny(host, hostPatternChars)
+}
+
+// matchHost reports whether the hostname matches the given host pattern.
+// Malformed patterns never match.
+func matchHost(pattern, hostname string) bool {
+	if pattern == hostname {
+		return true
+	}
+
+	ok, err := path.Match(pattern, hostname)
+	return err == nil && ok
+}
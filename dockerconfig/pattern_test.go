@@ -0,0 +1,117 @@
+package dockerconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupCredentialHelper registers a credential helper override, removing it on cleanup.
+func setupCredentialHelper(t *testing.T, host, helper string) {
+	t.Helper()
+
+	RegisterCredentialHelper(host, helper)
+	t.Cleanup(func() {
+		RegisterCredentialHelper(host, "")
+	})
+}
+
+func TestConfig_credentialHelper(t *testing.T) {
+	cfg := Config{
+		CredentialHelpers: map[string]string{
+			"123456789012.dkr.ecr.eu-west-1.amazonaws.com": "exact",
+			"*.dkr.ecr.*.amazonaws.com":                    "ecr-login",
+			"*.dkr.ecr.eu-west-1.amazonaws.com":            "ecr-login-eu",
+			"*-docker.pkg.dev":                             "gcloud",
+			"*.pkg.dev":                                    "gcloud-generic",
+			"[invalid":                                     "invalid",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		hostname string
+		want     string
+		wantOK   bool
+	}{
+		{name: "exact", hostname: "123456789012.dkr.ecr.eu-west-1.amazonaws.com", want: "exact", wantOK: true},
+		{name: "most-specific-pattern", hostname: "210987654321.dkr.ecr.eu-west-1.amazonaws.com", want: "ecr-login-eu", wantOK: true},
+		{name: "pattern", hostname: "210987654321.dkr.ecr.us-east-1.amazonaws.com", want: "ecr-login", wantOK: true},
+		{name: "longer-literal-wins", hostname: "europe-docker.pkg.dev", want: "gcloud", wantOK: true},
+		{name: "generic-pattern", hostname: "europe.pkg.dev", want: "gcloud-generic", wantOK: true},
+		{name: "no-match", hostname: "registry.io"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := cfg.credentialHelper(tc.hostname)
+			require.Equal(t, tc.wantOK, ok)
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("override/exact", func(t *testing.T) {
+		setupCredentialHelper(t, "123456789012.dkr.ecr.eu-west-1.amazonaws.com", "override")
+
+		got, ok := cfg.credentialHelper("123456789012.dkr.ecr.eu-west-1.amazonaws.com")
+		require.True(t, ok)
+		require.Equal(t, "override", got)
+	})
+
+	t.Run("override/pattern-after-config-exact", func(t *testing.T) {
+		setupCredentialHelper(t, "*.amazonaws.com", "override")
+
+		got, ok := cfg.credentialHelper("123456789012.dkr.ecr.eu-west-1.amazonaws.com")
+		require.True(t, ok)
+		require.Equal(t, "exact", got)
+
+		got, ok = cfg.credentialHelper("210987654321.dkr.ecr.us-east-1.amazonaws.com")
+		require.True(t, ok)
+		require.Equal(t, "override", got)
+	})
+
+	t.Run("override/removed", func(t *testing.T) {
+		RegisterCredentialHelper("registry.io", "override")
+		RegisterCredentialHelper("registry.io", "")
+
+		_, ok := cfg.credentialHelper("registry.io")
+		require.False(t, ok)
+	})
+}
+
+func TestConfig_GetRegistryCredentials_pattern(t *testing.T) {
+	mockExecCommand(t, `HELPER_STDOUT={"Username":"ecr","Secret":"ecrsecret"}`)
+
+	cfg := Config{
+		CredentialHelpers: map[string]string{
+			"*.dkr.ecr.*.amazonaws.com": "helper",
+		},
+	}
+
+	username, password, err := cfg.GetRegistryCredentials("123456789012.dkr.ecr.eu-west-1.amazonaws.com")
+	require.NoError(t, err)
+	require.Equal(t, "ecr", username)
+	require.Equal(t, "ecrsecret", password)
+}
+
+func TestPatternWeight(t *testing.T) {
+	tests := []struct {
+		pattern       string
+		wantLiterals  int
+		wantWildcards int
+	}{
+		{pattern: "registry.io", wantLiterals: 11},
+		{pattern: "*.registry.io", wantLiterals: 12, wantWildcards: 1},
+		{pattern: "?.registry.io", wantLiterals: 12, wantWildcards: 1},
+		{pattern: "[ab].registry.io", wantLiterals: 12, wantWildcards: 1},
+		{pattern: `\*.registry.io`, wantLiterals: 13},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern, func(t *testing.T) {
+			literals, wildcards := patternWeight(tc.pattern)
+			require.Equal(t, tc.wantLiterals, literals)
+			require.Equal(t, tc.wantWildcards, wildcards)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package dockerconfig
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// lockFileSuffix is the suffix of the sidecar lock file guarding the config file.
+const lockFileSuffix = ".lock"
+
+// Update applies fn to the docker config file in a cross-process safe way.
+//
+// It takes an advisory lock on a sidecar lock file, reloads the latest on-disk state,
+// applies fn and saves the result atomically, so concurrent updates never lose data.
+// If the config file doesn't exist yet, fn receives an empty config.
+// If fn returns an error, the config file is left untouched.
+//
+// The DOCKER_AUTH_CONFIG environment variable is not considered, as it can't be written.
+func Update(fn func(*Config) error) error {
+	p, err := Filepath()
+	if err != nil {
+		return fmt.Errorf("config path: %w", err)
+	}
+
+	return UpdateFilepath(p, fn)
+}
+
+// UpdateFilepath applies fn to the config file at the specified path.
+// See [Update] for details.
+func UpdateFilepath(configPath string, fn func(*Config) error) error {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+
+	unlock, err := lockFile(configPath + lockFileSuffix)
+	if err != nil {
+		return fmt.Errorf("lock config: %w", err)
+	}
+	defer unlock()
+
+	var cfg Config
+	if err := LoadFromFilepath(configPath, &cfg); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	if err := fn(&cfg); err != nil {
+		return err
+	}
+
+	return SaveToFilepath(configPath, cfg)
+}
+
+// lockFile takes an exclusive advisory lock on the given lock file, creating it if needed.
+// It blocks until the lock is acquired, and returns a function releasing it.
+func lockFile(lockPath string) (func(), error) {
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+
+	if err := lockFD(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("acquire lock: %w", err)
+	}
+
+	return func() {
+		_ = unlockFD(f)
+		f.Close()
+	}, nil
+}
+
+// Save writes the config to the default config file, which is ~/.docker/config.json,
+// or the one in the directory set by the DOCKER_CONFIG environment variable.
+//
+// Prefer [Update] when the config was read earlier, as Save overwrites any change
+// made by other processes in between.
+func Save(cfg Config) error {
+	p, err := Filepath()
+	if err != nil {
+		return fmt.Errorf("config path: %w", err)
+	}
+
+	return SaveToFilepath(p, cfg)
+}
+
+// SaveToFilepath writes cfg to the specified path atomically, keeping the keys of the
+// existing file that are not known to [Config] intact. If the path is a symlink,
+// the file it points to is written.
+func SaveToFilepath(configPath string, cfg Config) error {
+	if target, err := filepath.EvalSymlinks(configPath); err == nil {
+		configPath = target
+	}
+
+	raw, err := readRawConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	data, err := mergeRawConfig(raw, cfg)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(configPath, data)
+}
+
+// readRawConfig reads the config file at the specified path as raw JSON values by key.
+// A missing file is not an error.
+func readRawConfig(configPath string) (map[string]json.RawMessage, error) {
+	raw := map[string]json.RawMessage{}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return raw, nil
+		}
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	return raw, nil
+}
+
+// mergeRawConfig overlays cfg onto the raw config, returning the resulting JSON document.
+// Keys known to [Config] are replaced, or removed when empty, and unknown keys are kept.
+func mergeRawConfig(raw map[string]json.RawMessage, cfg Config) ([]byte, error) {
+	cfg.AuthConfigs = encodeAuthConfigs(cfg.AuthConfigs)
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	for _, key := range configKeys() {
+		delete(raw, key)
+	}
+
+	for key, value := range fields {
+		raw[key] = value
+	}
+
+	data, err = json.MarshalIndent(raw, "", "\t")
+	if err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+
+	return data, nil
+}
+
+// encodeAuthConfigs returns a copy of the auth entries as the docker CLI writes them:
+// the username and password are base64 encoded into "auth", the only field the docker
+// CLI reads them from, and the plaintext fields are cleared.
+func encodeAuthConfigs(auths map[string]AuthConfig) map[string]AuthConfig {
+	encoded := make(map[string]AuthConfig, len(auths))
+	for host, auth := range auths {
+		if auth.Username != "" || auth.Password != "" {
+			auth.Auth = base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+			auth.Username, auth.Password = "", ""
+		}
+		encoded[host] = auth
+	}
+
+	return encoded
+}
+
+// configKeys returns the JSON keys of the fields in [Config].
+func configKeys() []string {
+	t := reflect.TypeOf(Config{})
+
+	keys := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		keys = append(keys, name)
+	}
+
+	return keys
+}
+
+// writeFileAtomic writes data to a temporary file next to the given path,
+// and renames it into place, keeping the permissions of the existing file.
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp config: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp config: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp config: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp config: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return fmt.Errorf("chmod temp config: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename temp config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package dockerconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveToFilepath(t *testing.T) {
+	t.Run("new-file", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), FileName)
+
+		err := SaveToFilepath(configPath, Config{CurrentContext: "ctx"})
+		require.NoError(t, err)
+
+		raw := readRawConfigForTest(t, configPath)
+		require.JSONEq(t, `{}`, string(raw["auths"]))
+		require.JSONEq(t, `"ctx"`, string(raw["currentContext"]))
+
+		if runtime.GOOS != "windows" {
+			info, err := os.Stat(configPath)
+			require.NoError(t, err)
+			require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		}
+	})
+
+	t.Run("keeps-unknown-keys", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), FileName)
+		writeConfigForTest(t, configPath, `{"currentContext":"old","credsStore":"desktop","features":{"buildkit":"true"}}`)
+
+		var cfg Config
+		require.NoError(t, LoadFromFilepath(configPath, &cfg))
+		cfg.CurrentContext = ""
+
+		require.NoError(t, SaveToFilepath(configPath, cfg))
+
+		raw := readRawConfigForTest(t, configPath)
+		require.JSONEq(t, `{"buildkit":"true"}`, string(raw["features"]))
+		require.JSONEq(t, `"desktop"`, string(raw["credsStore"]))
+		require.NotContains(t, raw, "currentContext")
+	})
+
+	t.Run("encodes-auth", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), FileName)
+
+		cfg := Config{AuthConfigs: map[string]AuthConfig{
+			"registry.example.com": {Username: "user", Password: "pass", ServerAddress: "registry.example.com"},
+			"token.example.com":    {IdentityToken: "token"},
+			"legacy.example.com":   {Auth: "bGVnYWN5OnNlY3JldA=="},
+		}}
+		require.NoError(t, SaveToFilepath(configPath, cfg))
+
+		// the config passed in is not modified
+		require.Equal(t, "pass", cfg.AuthConfigs["registry.example.com"].Password)
+
+		raw := readRawConfigForTest(t, configPath)
+		require.JSONEq(t, `{
+			"registry.example.com": {"auth": "dXNlcjpwYXNz", "serveraddress": "registry.example.com"},
+			"token.example.com": {"identitytoken": "token"},
+			"legacy.example.com": {"auth": "bGVnYWN5OnNlY3JldA=="}
+		}`, string(raw["auths"]))
+
+		var loaded Config
+		require.NoError(t, LoadFromFilepath(configPath, &loaded))
+
+		username, password, err := loaded.GetRegistryCredentials("registry.example.com")
+		require.NoError(t, err)
+		require.Equal(t, "user", username)
+		require.Equal(t, "pass", password)
+	})
+
+	t.Run("symlink", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("symlinks require privileges on Windows")
+		}
+
+		dir := t.TempDir()
+		target := filepath.Join(dir, "real.json")
+		writeConfigForTest(t, target, `{"auths":{}}`)
+
+		configPath := filepath.Join(dir, FileName)
+		require.NoError(t, os.Symlink(target, configPath))
+
+		require.NoError(t, SaveToFilepath(configPath, Config{CurrentContext: "ctx"}))
+
+		info, err := os.Lstat(configPath)
+		require.NoError(t, err)
+		require.NotZero(t, info.Mode()&os.ModeSymlink)
+
+		raw := readRawConfigForTest(t, target)
+		require.JSONEq(t, `"ctx"`, string(raw["currentContext"]))
+	})
+
+	t.Run("invalid-config", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), FileName)
+		writeConfigForTest(t, configPath, `[]`)
+
+		err := SaveToFilepath(configPath, Config{})
+		require.ErrorContains(t, err, "decode config")
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("missing-file", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), ".docker")
+		setupDockerConfigs(t, dir)
+		t.Setenv("DOCKER_AUTH_CONFIG", "")
+
+		err := Update(func(cfg *Config) error {
+			cfg.CurrentContext = "ctx"
+			return nil
+		})
+		require.NoError(t, err)
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		require.Equal(t, "ctx", cfg.CurrentContext)
+	})
+
+	t.Run("reloads-latest-state", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), FileName)
+		writeConfigForTest(t, configPath, `{"auths":{"registry.io":{"auth":"dXNlcjpwYXNz"}}}`)
+
+		err := UpdateFilepath(configPath, func(cfg *Config) error {
+			require.Contains(t, cfg.AuthConfigs, "registry.io")
+			cfg.CurrentContext = "ctx"
+			return nil
+		})
+		require.NoError(t, err)
+
+		var cfg Config
+		require.NoError(t, LoadFromFilepath(configPath, &cfg))
+		require.Equal(t, "ctx", cfg.CurrentContext)
+		require.Equal(t, "dXNlcjpwYXNz", cfg.AuthConfigs["registry.io"].Auth)
+	})
+
+	t.Run("fn-error", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), FileName)
+		writeConfigForTest(t, configPath, `{"currentContext":"old"}`)
+
+		errUpdate := errors.New("update error")
+		err := UpdateFilepath(configPath, func(cfg *Config) error {
+			cfg.CurrentContext = "new"
+			return errUpdate
+		})
+		require.ErrorIs(t, err, errUpdate)
+
+		var cfg Config
+		require.NoError(t, LoadFromFilepath(configPath, &cfg))
+		require.Equal(t, "old", cfg.CurrentContext)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), FileName)
+
+		const updates = 20
+
+		var wg sync.WaitGroup
+		errs := make(chan error, updates)
+		for i := range updates {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- UpdateFilepath(configPath, func(cfg *Config) error {
+					if cfg.AuthConfigs == nil {
+						cfg.AuthConfigs = map[string]AuthConfig{}
+					}
+					cfg.AuthConfigs[fmt.Sprintf("registry%d.io", i)] = AuthConfig{Username: "user"}
+					return nil
+				})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		var cfg Config
+		require.NoError(t, LoadFromFilepath(configPath, &cfg))
+		require.Len(t, cfg.AuthConfigs, updates)
+	})
+}
+
+// writeConfigForTest writes the given content to the config file at the specified path.
+func writeConfigForTest(t *testing.T, configPath, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+}
+
+// readRawConfigForTest reads the config file at the specified path as raw JSON values by key.
+func readRawConfigForTest(t *testing.T, configPath string) map[string]json.RawMessage {
+	t.Helper()
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+
+	var raw map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &raw))
+
+	return raw
+}
@@ -0,0 +1,48 @@
+package dockercontext
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mdelapenya/docker-sdk-go/dockercontext/internal"
+)
+
+// Export writes the Docker context with the given name to w as a tar archive,
+// byte-compatible with "docker context export": its meta.json file and its TLS material.
+//
+// It returns [ErrContextNotFound] if there is no such context. The default context
+// is synthesized from the environment, so it cannot be exported.
+func Export(name string, w io.Writer) error {
+	if name == DefaultContextName {
+		return errors.New("the default context cannot be exported")
+	}
+
+	metaRoot, err := metaRoot()
+	if err != nil {
+		return fmt.Errorf("meta root: %w", err)
+	}
+
+	return internal.Export(name, metaRoot, w)
+}
+
+// Import reads a tar archive produced by "docker context export", or [Export],
+// from r and stores it as a new Docker context with the given name,
+// like "docker context import".
+//
+// Archives bigger than 10MiB, and entries with absolute paths or escaping the archive,
+// are rejected. As in the docker CLI, entries other than regular files, and files other
+// than the metadata and the TLS files, are skipped.
+// It returns [ErrContextExists] if a context with the same name already exists.
+func Import(name string, r io.Reader) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	metaRoot, err := metaRoot()
+	if err != nil {
+		return fmt.Errorf("meta root: %w", err)
+	}
+
+	return internal.Import(name, metaRoot, r)
+}
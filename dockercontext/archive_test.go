@@ -0,0 +1,53 @@
+package dockercontext
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	t.Run("round-trip", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		var buf bytes.Buffer
+		require.NoError(tt, Export("context1", &buf))
+		require.NoError(tt, Import("imported", &buf))
+
+		ctx, err := Inspect("imported")
+		require.NoError(tt, err)
+		require.Equal(tt, "Testcontainers Go 1", ctx.Description)
+		require.Equal(tt, "tcp://127.0.0.1:1", ctx.Endpoints["docker"].Host)
+	})
+
+	t.Run("export/default", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		require.Error(tt, Export(DefaultContextName, io.Discard))
+	})
+
+	t.Run("export/not-found", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		err := Export("context-not-found", io.Discard)
+		require.ErrorIs(tt, err, ErrContextNotFound)
+	})
+
+	t.Run("import/invalid-name", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		require.Error(tt, Import(DefaultContextName, &bytes.Buffer{}))
+	})
+
+	t.Run("import/already-exists", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		var buf bytes.Buffer
+		require.NoError(tt, Export("context1", &buf))
+
+		err := Import("context1", &buf)
+		require.ErrorIs(tt, err, ErrContextExists)
+	})
+}
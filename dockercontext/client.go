@@ -0,0 +1,161 @@
+package dockercontext
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+)
+
+// EnvOverrideAPIVersion is the name of the environment variable that can be used
+// to pin the version of the Docker API, e.g. "1.47", instead of negotiating it.
+const EnvOverrideAPIVersion = "DOCKER_API_VERSION"
+
+// defaultProxyKey is the key of the proxies of config.json used for any Docker host
+const defaultProxyKey = "default"
+
+// apiVersionRegEx is the format of the versions of the Docker API
+//
+//nolint:gochecknoglobals // Compiled once.
+var apiVersionRegEx = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+
+// ClientConfig gathers everything needed to talk to the Docker daemon,
+// as resolved by [Resolve].
+type ClientConfig struct {
+	// Endpoint is the Docker endpoint to connect to, with its host normalized by [ParseHost].
+	Endpoint DockerEndpoint
+
+	// Host is the parsed host of Endpoint.
+	Host Host
+
+	// APIVersion is the version of the Docker API pinned with DOCKER_API_VERSION, e.g. "1.47".
+	// It's empty when the version must be negotiated with the Docker daemon.
+	APIVersion string
+
+	// HTTPHeaders are the extra headers sent in every request to the Docker daemon,
+	// from the "HttpHeaders" of config.json.
+	HTTPHeaders map[string]string
+
+	// Proxy is the proxy configuration of the Docker daemon, from the "proxies" of
+	// config.json: the one of the Docker host, or the "default" one. As the docker CLI
+	// does, it's not used to connect to the Docker daemon, but passed to the containers
+	// and builds, see [ClientConfig.ProxyEnv].
+	Proxy dockerconfig.ProxyConfig
+}
+
+// Resolve resolves the configuration to talk to the Docker daemon, like the docker CLI:
+//
+//   - The endpoint of the current context, see [Current] and [ResolveDockerEndpoint], so the
+//     host and TLS configuration come from DOCKER_HOST, DOCKER_TLS_VERIFY, DOCKER_TLS and
+//     DOCKER_CERT_PATH for the default context, or from the context store otherwise.
+//   - The API version pinned with DOCKER_API_VERSION, if set.
+//   - The HTTP headers and proxies of config.json, if it exists.
+func Resolve() (ClientConfig, error) {
+	current, err := Current()
+	if err != nil {
+		return ClientConfig{}, fmt.Errorf("current context: %w", err)
+	}
+
+	ep, err := ResolveDockerEndpoint(current)
+	if err != nil {
+		return ClientConfig{}, fmt.Errorf("resolve endpoint: %w", err)
+	}
+
+	host, err := ParseHost(ep.TLSConfig != nil, ep.Host)
+	if err != nil {
+		return ClientConfig{}, err
+	}
+
+	cfg := ClientConfig{
+		Host:       host,
+		APIVersion: strings.TrimSpace(os.Getenv(EnvOverrideAPIVersion)),
+	}
+
+	if cfg.APIVersion != "" && !apiVersionRegEx.MatchString(cfg.APIVersion) {
+		return ClientConfig{}, fmt.Errorf("invalid %s %q: expected a version like 1.47", EnvOverrideAPIVersion, cfg.APIVersion)
+	}
+
+	dockerCfg, err := dockerconfig.Load()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return ClientConfig{}, fmt.Errorf("load docker config: %w", err)
+	}
+
+	ep.Host = host.String()
+	cfg.Endpoint = ep
+
+	cfg.HTTPHeaders = dockerCfg.HTTPHeaders
+	cfg.Proxy = proxyConfig(dockerCfg.Proxies, ep.Host)
+
+	return cfg, nil
+}
+
+// HTTPClient returns an [http.Client] connecting to the Docker daemon of the endpoint,
+// see [DockerEndpoint.HTTPClient], which sets the HTTPHeaders in every request.
+func (c ClientConfig) HTTPClient() (*http.Client, error) {
+	transport, err := c.Endpoint.Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.HTTPHeaders) == 0 {
+		return &http.Client{Transport: transport}, nil
+	}
+
+	return &http.Client{Transport: &headerTransport{base: transport, headers: c.HTTPHeaders}}, nil
+}
+
+// ProxyEnv returns the environment variables of the proxy configuration, e.g. "HTTP_PROXY=...",
+// in upper and lower case, as the docker CLI passes them to the containers and builds.
+func (c ClientConfig) ProxyEnv() []string {
+	var env []string
+	for _, v := range []struct{ name, value string }{
+		{"HTTP_PROXY", c.Proxy.HTTPProxy},
+		{"HTTPS_PROXY", c.Proxy.HTTPSProxy},
+		{"NO_PROXY", c.Proxy.NoProxy},
+		{"FTP_PROXY", c.Proxy.FTPProxy},
+	} {
+		if v.value != "" {
+			env = append(env, v.name+"="+v.value, strings.ToLower(v.name)+"="+v.value)
+		}
+	}
+
+	return env
+}
+
+// proxyConfig returns the proxy configuration of the given normalized Docker host,
+// e.g. "tcp://127.0.0.1:2375", or the default one, as the docker CLI does.
+func proxyConfig(proxies map[string]dockerconfig.ProxyConfig, host string) dockerconfig.ProxyConfig {
+	if proxy, ok := proxies[host]; ok {
+		return proxy
+	}
+
+	return proxies[defaultProxyKey]
+}
+
+// headerTransport sets extra headers in every request
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip implements [http.RoundTripper], setting the headers in a copy of the request.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the base transport, if supported.
+func (t *headerTransport) CloseIdleConnections() {
+	if ci, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		ci.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,179 @@
+package dockercontext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+)
+
+func TestResolve(t *testing.T) {
+	t.Run("default", func(tt *testing.T) {
+		setupClientConfig(tt, `{
+	"HttpHeaders": {"X-Meta-Source": "tests"},
+	"proxies": {
+		"default": {"httpProxy": "http://proxy:3128", "noProxy": "localhost"},
+		"tcp://127.0.0.1:2376": {"httpsProxy": "http://other-proxy:3128"}
+	}
+}`)
+		setupOverrides(tt, "tcp://127.0.0.1", "")
+		tt.Setenv(EnvOverrideAPIVersion, "1.45")
+
+		cfg, err := Resolve()
+		require.NoError(tt, err)
+		require.Equal(tt, ClientConfig{
+			Endpoint:    DockerEndpoint{ContextName: DefaultContextName, Host: "tcp://127.0.0.1:2375"},
+			Host:        Host{Proto: "tcp", Addr: "127.0.0.1:2375"},
+			APIVersion:  "1.45",
+			HTTPHeaders: map[string]string{"X-Meta-Source": "tests"},
+			Proxy:       dockerconfig.ProxyConfig{HTTPProxy: "http://proxy:3128", NoProxy: "localhost"},
+		}, cfg)
+		require.Equal(tt, []string{
+			"HTTP_PROXY=http://proxy:3128", "http_proxy=http://proxy:3128",
+			"NO_PROXY=localhost", "no_proxy=localhost",
+		}, cfg.ProxyEnv())
+	})
+
+	t.Run("default/tls", func(tt *testing.T) {
+		setupClientConfig(tt, `{"proxies": {"tcp://127.0.0.1:2376": {"httpsProxy": "http://proxy:3128"}}}`)
+
+		certPath := tt.TempDir()
+		writeCertificates(tt, certPath)
+
+		setupOverrides(tt, "tcp://127.0.0.1:2376", "")
+		tt.Setenv(EnvTLSVerify, "1")
+		tt.Setenv(EnvOverrideCertPath, certPath)
+
+		cfg, err := Resolve()
+		require.NoError(tt, err)
+		require.Equal(tt, "tcp://127.0.0.1:2376", cfg.Endpoint.Host)
+		require.NotNil(tt, cfg.Endpoint.TLSConfig)
+		require.Empty(tt, cfg.APIVersion)
+		require.Equal(tt, dockerconfig.ProxyConfig{HTTPSProxy: "http://proxy:3128"}, cfg.Proxy)
+	})
+
+	t.Run("default/normalized-proxy-host", func(tt *testing.T) {
+		setupClientConfig(tt, `{
+	"proxies": {
+		"default": {"httpProxy": "http://proxy:3128"},
+		"tcp://127.0.0.1:2375": {"httpProxy": "http://host-proxy:3128"}
+	}
+}`)
+		setupOverrides(tt, "tcp://127.0.0.1", "")
+		tt.Setenv(EnvOverrideAPIVersion, "")
+
+		cfg, err := Resolve()
+		require.NoError(tt, err)
+		require.Equal(tt, "tcp://127.0.0.1:2375", cfg.Endpoint.Host)
+		require.Equal(tt, dockerconfig.ProxyConfig{HTTPProxy: "http://host-proxy:3128"}, cfg.Proxy)
+	})
+
+	t.Run("context", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+		setupOverrides(tt, "", "")
+		tt.Setenv(EnvOverrideAPIVersion, "")
+
+		cfg, err := Resolve()
+		require.NoError(tt, err)
+		require.Equal(tt, ClientConfig{
+			Endpoint: DockerEndpoint{ContextName: "context1", Host: "tcp://127.0.0.1:1"},
+			Host:     Host{Proto: "tcp", Addr: "127.0.0.1:1"},
+		}, cfg)
+	})
+
+	t.Run("no-config-file", func(tt *testing.T) {
+		setupClientConfig(tt, "")
+		setupOverrides(tt, "unix:///run/docker.sock", "")
+
+		cfg, err := Resolve()
+		require.NoError(tt, err)
+		require.Equal(tt, "unix:///run/docker.sock", cfg.Endpoint.Host)
+		require.Empty(tt, cfg.HTTPHeaders)
+	})
+
+	t.Run("invalid-api-version", func(tt *testing.T) {
+		setupClientConfig(tt, "")
+		setupOverrides(tt, "unix:///run/docker.sock", "")
+		tt.Setenv(EnvOverrideAPIVersion, "latest")
+
+		_, err := Resolve()
+		require.ErrorContains(tt, err, `invalid DOCKER_API_VERSION "latest"`)
+	})
+
+	t.Run("invalid-host", func(tt *testing.T) {
+		setupClientConfig(tt, "")
+		setupOverrides(tt, "tpc://127.0.0.1:2375", "")
+
+		_, err := Resolve()
+		require.ErrorContains(tt, err, `unsupported protocol "tpc"`)
+	})
+
+	t.Run("context-not-found", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+		setupOverrides(tt, "", "context-not-found")
+
+		_, err := Resolve()
+		require.ErrorIs(tt, err, ErrContextNotFound)
+	})
+}
+
+func TestClientConfig_HTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Got-Meta-Source", r.Header.Get("X-Meta-Source"))
+		w.Header().Set("X-Got-User-Agent", r.Header.Get("User-Agent"))
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := ClientConfig{
+		Endpoint:    DockerEndpoint{Host: "tcp://" + srv.Listener.Addr().String()},
+		HTTPHeaders: map[string]string{"X-Meta-Source": "tests", "User-Agent": "custom"},
+	}
+
+	client, err := cfg.HTTPClient()
+	require.NoError(t, err)
+	t.Cleanup(client.CloseIdleConnections)
+
+	u, err := cfg.Endpoint.URL()
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, u.String()+"/_ping", nil) //nolint:noctx // test request
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "default")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	require.Equal(t, "tests", resp.Header.Get("X-Got-Meta-Source"))
+	require.Equal(t, "custom", resp.Header.Get("X-Got-User-Agent"))
+	require.Equal(t, "default", req.Header.Get("User-Agent")) // the request is not modified
+}
+
+// setupClientConfig sets up an empty home directory with the given config.json,
+// if not empty, clearing the environment variables of the client configuration.
+func setupClientConfig(t *testing.T, configJSON string) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home) // Windows support
+	t.Setenv("DOCKER_CONFIG", "")
+	t.Setenv("DOCKER_AUTH_CONFIG", "")
+	t.Setenv(EnvTLSVerify, "")
+	t.Setenv(EnvOverrideCertPath, "")
+	t.Setenv(EnvOverrideAPIVersion, "")
+
+	if configJSON != "" {
+		configDir, err := dockerconfig.Dir()
+		require.NoError(t, err)
+		tempMkdirAll(t, configDir)
+
+		err = os.WriteFile(filepath.Join(configDir, dockerconfig.FileName), []byte(configJSON), 0o600)
+		require.NoError(t, err)
+	}
+}
@@ -5,7 +5,9 @@ package dockercontext
 // with the goal of not consuming the CLI package and all its dependencies.
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -31,16 +33,55 @@ const (
 	// specific), or any host already set.
 	EnvOverrideHost = "DOCKER_HOST"
 
+	// EnvTLSVerify is the name of the environment variable that can be used to
+	// enable or disable TLS certificate verification. When set to a non-empty
+	// value, TLS certificate verification is enabled for the default context,
+	// and the client is configured to use TLS to connect to the daemon.
+	EnvTLSVerify = "DOCKER_TLS_VERIFY"
+
+	// EnvEnableTLS is the name of the environment variable that can be used to
+	// enable TLS without verifying the certificate of the daemon, as the docker
+	// CLI does. It's implied by EnvTLSVerify, which enables the verification.
+	EnvEnableTLS = "DOCKER_TLS"
+
+	// EnvOverrideCertPath is the name of the environment variable that can be
+	// used to specify the directory from which to load the TLS certificates
+	// (ca.pem, cert.pem, key.pem) of the default context. It defaults to the
+	// Docker configuration directory.
+	EnvOverrideCertPath = "DOCKER_CERT_PATH"
+
+	// DefaultTLSHost is the host of the default context when TLS is enabled
+	// with EnvTLSVerify or EnvEnableTLS but no host is set with EnvOverrideHost.
+	DefaultTLSHost = "tcp://localhost:2376"
+
 	// contextsDir is the name of the directory containing the contexts
 	contextsDir = "contexts"
 
 	// metadataDir is the name of the directory containing the metadata
 	metadataDir = "meta"
+
+	// dockerEndpoint is the name of the endpoint to connect to the Docker daemon
+	dockerEndpoint = "docker"
+
+	// defaultContextDescription is the description of the default context, as set by the docker CLI
+	defaultContextDescription = "Current DOCKER_HOST based configuration"
 )
 
+// Context represents a Docker context, with its typed metadata.
+type Context = internal.Context
+
+// Endpoint represents an endpoint of a Docker context.
+type Endpoint = internal.Endpoint
+
+// Storage holds the paths where a Docker context is stored.
+type Storage = internal.Storage
+
 // ErrDockerHostNotSet is the error returned when the Docker host is not set in the Docker context
 var ErrDockerHostNotSet = internal.ErrDockerHostNotSet
 
+// ErrContextNotFound is the error returned when the Docker context does not exist
+var ErrContextNotFound = internal.ErrContextNotFound
+
 // getContextFromEnv returns the context name from the environment variables.
 func getContextFromEnv() string {
 	if os.Getenv(EnvOverrideHost) != "" {
@@ -68,7 +109,7 @@ func Current() (string, error) {
 	// Then check config
 	cfg, err := dockerconfig.Load()
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return DefaultContextName, nil
 		}
 		return "", fmt.Errorf("load docker config: %w", err)
@@ -84,12 +125,22 @@ func Current() (string, error) {
 // CurrentDockerHost returns the Docker host from the current Docker context.
 // For that, it traverses the directory structure of the Docker configuration directory,
 // looking for the current context and its Docker endpoint.
+//
+// For the default context, it returns the host resolved from the environment,
+// as the docker CLI does: see [DefaultDockerHost] and [DefaultTLSHost].
+//
+// It returns [ErrContextNotFound] if the current context does not exist,
+// and [ErrDockerHostNotSet] if it has no Docker host.
 func CurrentDockerHost() (string, error) {
 	current, err := Current()
 	if err != nil {
 		return "", fmt.Errorf("current context: %w", err)
 	}
 
+	if current == DefaultContextName {
+		return defaultDockerHost(), nil
+	}
+
 	metaRoot, err := metaRoot()
 	if err != nil {
 		return "", fmt.Errorf("meta root: %w", err)
@@ -98,6 +149,39 @@ func CurrentDockerHost() (string, error) {
 	return internal.ExtractDockerHost(current, metaRoot)
 }
 
+// List returns all the Docker contexts in the context store, like "docker context ls".
+// The default context, which is not stored but synthesized from the environment,
+// always comes first, followed by the stored contexts sorted by name.
+func List() ([]Context, error) {
+	metaRoot, err := metaRoot()
+	if err != nil {
+		return nil, fmt.Errorf("meta root: %w", err)
+	}
+
+	contexts, err := internal.List(metaRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Context{defaultContext()}, contexts...), nil
+}
+
+// Inspect returns the Docker context with the given name, like "docker context inspect".
+// It returns [ErrContextNotFound] if there is no such context. The default context is
+// synthesized from the environment, so it has no storage paths.
+func Inspect(name string) (Context, error) {
+	if name == DefaultContextName {
+		return defaultContext(), nil
+	}
+
+	metaRoot, err := metaRoot()
+	if err != nil {
+		return Context{}, fmt.Errorf("meta root: %w", err)
+	}
+
+	return internal.Inspect(name, metaRoot)
+}
+
 // metaRoot returns the root directory of the Docker context metadata.
 func metaRoot() (string, error) {
 	dir, err := dockerconfig.Dir()
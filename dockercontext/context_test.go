@@ -54,6 +54,17 @@ func TestCurrent(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, DefaultContextName, current)
 	})
+
+	t.Run("current/no-config-file", func(tt *testing.T) {
+		// the wrapped not-exist error of the missing config file falls back to the default context
+		tt.Setenv("DOCKER_CONFIG", tt.TempDir())
+		tt.Setenv("DOCKER_AUTH_CONFIG", "")
+		setupOverrides(tt, "", "")
+
+		current, err := Current()
+		require.NoError(tt, err)
+		require.Equal(tt, DefaultContextName, current)
+	})
 }
 
 func TestCurrentDockerHost(t *testing.T) {
@@ -80,9 +91,95 @@ func TestCurrentDockerHost(t *testing.T) {
 		require.NoError(t, err)
 
 		host, err := internal.ExtractDockerHost("context-not-found", metaRoot)
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrContextNotFound)
 		require.Empty(t, host)
 	})
+
+	t.Run("docker-context/not-set", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+		tt.Setenv(EnvOverrideContext, "context2") // the context with no host
+
+		host, err := CurrentDockerHost()
+		require.ErrorIs(tt, err, ErrDockerHostNotSet)
+		require.Empty(tt, host)
+	})
+}
+
+func TestList(t *testing.T) {
+	t.Run("contexts", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		tt.Setenv(EnvOverrideHost, "")
+
+		contexts, err := List()
+		require.NoError(tt, err)
+		require.Len(tt, contexts, 4) // default, context1, context2 and the context with no host
+
+		require.Equal(tt, DefaultContextName, contexts[0].Name)
+		require.Equal(tt, DefaultDockerHost, contexts[0].Endpoints["docker"].Host)
+
+		require.Equal(tt, "context1", contexts[1].Name)
+		require.Equal(tt, "Testcontainers Go 1", contexts[1].Description)
+		require.Equal(tt, "tcp://127.0.0.1:1", contexts[1].Endpoints["docker"].Host)
+
+		require.Equal(tt, "context2", contexts[2].Name)
+		require.Equal(tt, "context3", contexts[3].Name)
+		require.Empty(tt, contexts[3].Endpoints["docker"].Host)
+	})
+
+	t.Run("default/override-host", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+		tt.Setenv(EnvOverrideHost, "tcp://127.0.0.1:123")
+
+		contexts, err := List()
+		require.NoError(tt, err)
+		require.Equal(tt, DefaultContextName, contexts[0].Name)
+		require.Equal(tt, "tcp://127.0.0.1:123", contexts[0].Endpoints["docker"].Host)
+	})
+
+	t.Run("no-contexts", func(tt *testing.T) {
+		tmpDir := tt.TempDir()
+		tt.Setenv("HOME", tmpDir)
+		tt.Setenv("USERPROFILE", tmpDir) // Windows support
+
+		contexts, err := List()
+		require.NoError(tt, err)
+		require.Len(tt, contexts, 1)
+		require.Equal(tt, DefaultContextName, contexts[0].Name)
+	})
+}
+
+func TestInspect(t *testing.T) {
+	t.Run("found", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+
+		ctx, err := Inspect("context2")
+		require.NoError(tt, err)
+		require.Equal(tt, "context2", ctx.Name)
+		require.Equal(tt, "Testcontainers Go 2", ctx.Description)
+		require.Equal(tt, "tcp://127.0.0.1:2", ctx.Endpoints["docker"].Host)
+
+		metaDir, err := metaRoot()
+		require.NoError(tt, err)
+		require.Equal(tt, filepath.Join(metaDir, "context2"), ctx.Storage.MetadataPath)
+	})
+
+	t.Run("default", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		tt.Setenv(EnvOverrideHost, "")
+
+		ctx, err := Inspect(DefaultContextName)
+		require.NoError(tt, err)
+		require.Equal(tt, DefaultContextName, ctx.Name)
+		require.Equal(tt, DefaultDockerHost, ctx.Endpoints["docker"].Host)
+		require.Empty(tt, ctx.Storage)
+	})
+
+	t.Run("not-found", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+
+		_, err := Inspect("context-not-found")
+		require.ErrorIs(tt, err, ErrContextNotFound)
+	})
 }
 
 // setupDockerContexts creates a temporary directory structure for testing the Docker context functions.
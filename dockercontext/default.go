@@ -0,0 +1,94 @@
+package dockercontext
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+	"github.com/mdelapenya/docker-sdk-go/dockercontext/internal"
+)
+
+// defaultContext returns the default context, which is synthesized from the
+// environment with the same precedence rules as the docker CLI.
+func defaultContext() Context {
+	return Context{
+		Name:        DefaultContextName,
+		Description: defaultContextDescription,
+		Endpoints: map[string]Endpoint{
+			dockerEndpoint: {Host: defaultDockerHost()},
+		},
+	}
+}
+
+// defaultDockerHost returns the Docker host of the default context: the DOCKER_HOST
+// environment variable, or, when it's not set, [DefaultTLSHost] if DOCKER_TLS_VERIFY
+// or DOCKER_TLS is set, or the platform's [DefaultDockerHost] otherwise.
+func defaultDockerHost() string {
+	if host := strings.TrimSpace(os.Getenv(EnvOverrideHost)); host != "" {
+		return host
+	}
+
+	if defaultTLSEnabled() {
+		return DefaultTLSHost
+	}
+
+	return DefaultDockerHost
+}
+
+// defaultTLSVerify reports whether TLS is enabled for the default context, verifying the Docker daemon.
+func defaultTLSVerify() bool {
+	return os.Getenv(EnvTLSVerify) != ""
+}
+
+// defaultTLSEnabled reports whether TLS is enabled for the default context, with
+// DOCKER_TLS_VERIFY, or with DOCKER_TLS to skip the verification of the Docker daemon.
+func defaultTLSEnabled() bool {
+	return defaultTLSVerify() || os.Getenv(EnvEnableTLS) != ""
+}
+
+// defaultTLSConfig returns the TLS configuration of the default context. When
+// DOCKER_TLS_VERIFY or DOCKER_TLS is set, it loads the ca.pem, cert.pem and key.pem
+// files from the DOCKER_CERT_PATH directory, or the Docker configuration directory
+// if not set. With DOCKER_TLS only, the certificate of the Docker daemon is not verified.
+// It returns nil when TLS is not enabled.
+func defaultTLSConfig() (*tls.Config, error) {
+	if !defaultTLSEnabled() {
+		return nil, nil
+	}
+
+	certPath := os.Getenv(EnvOverrideCertPath)
+	if certPath == "" {
+		dir, err := dockerconfig.Dir()
+		if err != nil {
+			return nil, fmt.Errorf("docker config dir: %w", err)
+		}
+		certPath = dir
+	}
+
+	cfg, err := tlsConfigFromDir(certPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.InsecureSkipVerify = !defaultTLSVerify() //nolint:gosec // Explicitly requested with DOCKER_TLS.
+
+	return cfg, nil
+}
+
+// tlsConfigFromDir returns a TLS configuration verifying the Docker daemon, with the
+// ca.pem, cert.pem and key.pem files of the given directory. If there are none,
+// the system's root CAs are used.
+func tlsConfigFromDir(certPath string) (*tls.Config, error) {
+	data, err := internal.LoadTLSDataFromDir(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("load tls data: %w", err)
+	}
+
+	if data == nil {
+		// TLS is enabled, with the system's root CAs
+		data = &internal.TLSData{}
+	}
+
+	return internal.TLSConfig(data, false)
+}
@@ -0,0 +1,148 @@
+package dockercontext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+)
+
+func TestDefaultDockerHost(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		tlsVerify string
+		tls       string
+		want      string
+	}{
+		{name: "platform-default", want: DefaultDockerHost},
+		{name: "docker-host", host: "tcp://1.2.3.4:2375", want: "tcp://1.2.3.4:2375"},
+		{name: "docker-host/trimmed", host: " tcp://1.2.3.4:2375 ", want: "tcp://1.2.3.4:2375"},
+		{name: "tls-verify", tlsVerify: "1", want: DefaultTLSHost},
+		{name: "docker-host/tls-verify", host: "tcp://1.2.3.4:2376", tlsVerify: "1", want: "tcp://1.2.3.4:2376"},
+		{name: "tls", tls: "1", want: DefaultTLSHost},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tt *testing.T) {
+			tt.Setenv(EnvOverrideHost, tc.host)
+			tt.Setenv(EnvTLSVerify, tc.tlsVerify)
+			tt.Setenv(EnvEnableTLS, tc.tls)
+
+			require.Equal(tt, tc.want, defaultDockerHost())
+		})
+	}
+}
+
+func TestDefaultTLSConfig(t *testing.T) {
+	t.Run("disabled", func(tt *testing.T) {
+		tt.Setenv(EnvTLSVerify, "")
+		tt.Setenv(EnvEnableTLS, "")
+
+		cfg, err := defaultTLSConfig()
+		require.NoError(tt, err)
+		require.Nil(tt, cfg)
+	})
+
+	t.Run("cert-path", func(tt *testing.T) {
+		certPath := tt.TempDir()
+		writeCertificates(tt, certPath)
+
+		tt.Setenv(EnvTLSVerify, "1")
+		tt.Setenv(EnvOverrideCertPath, certPath)
+
+		cfg, err := defaultTLSConfig()
+		require.NoError(tt, err)
+		require.False(tt, cfg.InsecureSkipVerify)
+		require.NotNil(tt, cfg.RootCAs)
+		require.Len(tt, cfg.Certificates, 1)
+	})
+
+	t.Run("config-dir", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		configDir, err := dockerconfig.Dir()
+		require.NoError(tt, err)
+		writeCertificates(tt, configDir)
+
+		tt.Setenv(EnvTLSVerify, "1")
+		tt.Setenv(EnvOverrideCertPath, "")
+
+		cfg, err := defaultTLSConfig()
+		require.NoError(tt, err)
+		require.NotNil(tt, cfg.RootCAs)
+		require.Len(tt, cfg.Certificates, 1)
+	})
+
+	t.Run("tls", func(tt *testing.T) {
+		certPath := tt.TempDir()
+		writeCertificates(tt, certPath)
+
+		tt.Setenv(EnvTLSVerify, "")
+		tt.Setenv(EnvEnableTLS, "1")
+		tt.Setenv(EnvOverrideCertPath, certPath)
+
+		cfg, err := defaultTLSConfig()
+		require.NoError(tt, err)
+		require.True(tt, cfg.InsecureSkipVerify)
+		require.Len(tt, cfg.Certificates, 1)
+	})
+
+	t.Run("tls/tls-verify", func(tt *testing.T) {
+		tt.Setenv(EnvTLSVerify, "1")
+		tt.Setenv(EnvEnableTLS, "1")
+		tt.Setenv(EnvOverrideCertPath, tt.TempDir())
+
+		cfg, err := defaultTLSConfig()
+		require.NoError(tt, err)
+		require.False(tt, cfg.InsecureSkipVerify)
+	})
+
+	t.Run("no-certificates", func(tt *testing.T) {
+		tt.Setenv(EnvTLSVerify, "1")
+		tt.Setenv(EnvOverrideCertPath, tt.TempDir())
+
+		cfg, err := defaultTLSConfig()
+		require.NoError(tt, err)
+		require.False(tt, cfg.InsecureSkipVerify)
+		require.Nil(tt, cfg.RootCAs) // system roots
+		require.Empty(tt, cfg.Certificates)
+	})
+}
+
+func TestCurrentDockerHost_default(t *testing.T) {
+	t.Run("docker-host", func(tt *testing.T) {
+		tt.Setenv(EnvOverrideHost, "tcp://127.0.0.1:123")
+
+		host, err := CurrentDockerHost()
+		require.NoError(tt, err)
+		require.Equal(tt, "tcp://127.0.0.1:123", host)
+	})
+
+	t.Run("platform-default", func(tt *testing.T) {
+		setupDockerContexts(tt, 2, 1) // no current context
+		setupOverrides(tt, "", "")
+		tt.Setenv(EnvTLSVerify, "")
+		tt.Setenv(EnvEnableTLS, "")
+
+		host, err := CurrentDockerHost()
+		require.NoError(tt, err)
+		require.Equal(tt, DefaultDockerHost, host)
+	})
+}
+
+// writeCertificates copies the self-signed CA, certificate and key of the internal
+// testdata directory into the given directory
+func writeCertificates(t *testing.T, dir string) {
+	t.Helper()
+
+	tempMkdirAll(t, dir)
+	for _, name := range []string{"ca.pem", "cert.pem", "key.pem"} {
+		data, err := os.ReadFile(filepath.Join("internal", "testdata", "tls", name))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+	}
+}
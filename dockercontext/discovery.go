@@ -0,0 +1,303 @@
+package dockercontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	// EnvColimaProfile is the name of the environment variable used by Colima
+	// to select the profile, whose socket is discovered by [ColimaStrategy].
+	EnvColimaProfile = "COLIMA_PROFILE"
+
+	// defaultColimaProfile is the name of the profile used by Colima when none is set
+	defaultColimaProfile = "default"
+
+	// socketDialTimeout is the timeout to check if a socket is accepting connections
+	socketDialTimeout = time.Second
+)
+
+// ErrHostNotDiscovered is returned by a [HostStrategy] that doesn't apply or
+// finds no usable host, so the discovery continues with the next strategy.
+// It's also returned by [DiscoverDockerHost] when no strategy finds a host.
+var ErrHostNotDiscovered = errors.New("docker host not discovered")
+
+// HostStrategy is a way to discover the Docker host to connect to.
+type HostStrategy struct {
+	// Name identifies the strategy in the [Discovery] result, e.g. "colima".
+	Name string
+
+	// Discover returns the Docker endpoint found by the strategy, or an error wrapping
+	// [ErrHostNotDiscovered] if the strategy doesn't apply. Any other error stops the discovery.
+	Discover func() (DockerEndpoint, error)
+}
+
+// Discovery is the result of discovering the Docker host.
+type Discovery struct {
+	// Strategy is the name of the strategy that found the Docker host.
+	Strategy string
+
+	// Endpoint is the Docker endpoint found by the strategy.
+	Endpoint DockerEndpoint
+}
+
+// DefaultStrategies returns the strategies used by [DiscoverDockerHost] when none is passed,
+// in the order they are tried:
+//
+//   - [EnvStrategy]: the DOCKER_HOST, DOCKER_TLS_VERIFY and DOCKER_TLS environment variables.
+//   - [ContextStrategy]: the current Docker context, unless it's the default one.
+//   - [DefaultSocketStrategy]: the platform's [DefaultDockerHost].
+//   - [RootlessStrategy]: rootless Docker, in $XDG_RUNTIME_DIR/docker.sock.
+//   - [DockerDesktopStrategy]: Docker Desktop, in ~/.docker/run/docker.sock.
+//   - [ColimaStrategy]: Colima, in ~/.colima/<profile>/docker.sock.
+//   - [RancherDesktopStrategy]: Rancher Desktop, in ~/.rd/docker.sock.
+//   - [PodmanStrategy]: Podman's Docker compatible socket.
+func DefaultStrategies() []HostStrategy {
+	return []HostStrategy{
+		EnvStrategy(),
+		ContextStrategy(),
+		DefaultSocketStrategy(),
+		RootlessStrategy(),
+		DockerDesktopStrategy(),
+		ColimaStrategy(""),
+		RancherDesktopStrategy(),
+		PodmanStrategy(),
+	}
+}
+
+// DiscoverDockerHost tries the given strategies in order, returning the Docker endpoint
+// found by the first one that applies, and the name of that strategy.
+// If no strategy is passed, it uses [DefaultStrategies].
+//
+// It returns [ErrHostNotDiscovered] if no strategy finds a Docker host.
+func DiscoverDockerHost(strategies ...HostStrategy) (Discovery, error) {
+	if len(strategies) == 0 {
+		strategies = DefaultStrategies()
+	}
+
+	for _, strategy := range strategies {
+		ep, err := strategy.Discover()
+		if err != nil {
+			if errors.Is(err, ErrHostNotDiscovered) {
+				continue
+			}
+			return Discovery{}, fmt.Errorf("strategy %q: %w", strategy.Name, err)
+		}
+
+		return Discovery{Strategy: strategy.Name, Endpoint: ep}, nil
+	}
+
+	return Discovery{}, ErrHostNotDiscovered
+}
+
+// EnvStrategy discovers the Docker host from the DOCKER_HOST environment variable,
+// or [DefaultTLSHost] if only DOCKER_TLS_VERIFY or DOCKER_TLS is set, as the default context does.
+func EnvStrategy() HostStrategy {
+	return HostStrategy{
+		Name: "env",
+		Discover: func() (DockerEndpoint, error) {
+			if os.Getenv(EnvOverrideHost) == "" && !defaultTLSEnabled() {
+				return DockerEndpoint{}, fmt.Errorf("%s not set: %w", EnvOverrideHost, ErrHostNotDiscovered)
+			}
+
+			return ResolveDockerEndpoint(DefaultContextName)
+		},
+	}
+}
+
+// ContextStrategy discovers the Docker host from the current Docker context,
+// selected with DOCKER_CONTEXT or in the config file. It doesn't apply to the
+// default context, so the well-known sockets can be tried after it.
+//
+// A current context that doesn't exist, or has no Docker host, stops the discovery.
+func ContextStrategy() HostStrategy {
+	return HostStrategy{
+		Name: "context",
+		Discover: func() (DockerEndpoint, error) {
+			current, err := Current()
+			if err != nil {
+				return DockerEndpoint{}, fmt.Errorf("current context: %w", err)
+			}
+
+			if current == DefaultContextName {
+				return DockerEndpoint{}, fmt.Errorf("default context: %w", ErrHostNotDiscovered)
+			}
+
+			return ResolveDockerEndpoint(current)
+		},
+	}
+}
+
+// SocketStrategy discovers the Docker host from the first of the given socket paths
+// that accepts connections. Paths that don't exist, aren't sockets, or refuse
+// connections are skipped. Relative paths are resolved from the user's home directory.
+func SocketStrategy(name string, paths ...string) HostStrategy {
+	return HostStrategy{
+		Name: name,
+		Discover: func() (DockerEndpoint, error) {
+			return discoverSocket(paths...)
+		},
+	}
+}
+
+// DefaultSocketStrategy discovers the Docker host from the platform's [DefaultDockerHost],
+// if it accepts connections.
+func DefaultSocketStrategy() HostStrategy {
+	return HostStrategy{
+		Name: "default-socket",
+		Discover: func() (DockerEndpoint, error) {
+			if err := checkSocket(DefaultDockerHost); err != nil {
+				return DockerEndpoint{}, err
+			}
+
+			return DockerEndpoint{Host: DefaultDockerHost}, nil
+		},
+	}
+}
+
+// RootlessStrategy discovers the Docker host of rootless Docker, in $XDG_RUNTIME_DIR/docker.sock,
+// or /run/user/<uid>/docker.sock if XDG_RUNTIME_DIR is not set.
+func RootlessStrategy() HostStrategy {
+	return HostStrategy{
+		Name: "rootless",
+		Discover: func() (DockerEndpoint, error) {
+			dir := runtimeDir()
+			if dir == "" {
+				return DockerEndpoint{}, fmt.Errorf("no runtime dir: %w", ErrHostNotDiscovered)
+			}
+
+			return discoverSocket(filepath.Join(dir, "docker.sock"))
+		},
+	}
+}
+
+// DockerDesktopStrategy discovers the Docker host of Docker Desktop, in ~/.docker/run/docker.sock.
+func DockerDesktopStrategy() HostStrategy {
+	return SocketStrategy("docker-desktop", filepath.Join(".docker", "run", "docker.sock"))
+}
+
+// ColimaStrategy discovers the Docker host of the given Colima profile, in ~/.colima/<profile>/docker.sock.
+// If the profile is empty, it's read from the COLIMA_PROFILE environment variable,
+// defaulting to "default".
+func ColimaStrategy(profile string) HostStrategy {
+	return HostStrategy{
+		Name: "colima",
+		Discover: func() (DockerEndpoint, error) {
+			p := profile
+			if p == "" {
+				p = os.Getenv(EnvColimaProfile)
+			}
+			if p == "" {
+				p = defaultColimaProfile
+			}
+
+			return discoverSocket(filepath.Join(".colima", p, "docker.sock"))
+		},
+	}
+}
+
+// RancherDesktopStrategy discovers the Docker host of Rancher Desktop, in ~/.rd/docker.sock.
+func RancherDesktopStrategy() HostStrategy {
+	return SocketStrategy("rancher-desktop", filepath.Join(".rd", "docker.sock"))
+}
+
+// PodmanStrategy discovers the Docker compatible host of Podman: the rootless socket
+// in $XDG_RUNTIME_DIR/podman/podman.sock, the rootful one in /run/podman/podman.sock,
+// or the one of the Podman machine in ~/.local/share/containers/podman/machine/podman.sock.
+func PodmanStrategy() HostStrategy {
+	return HostStrategy{
+		Name: "podman",
+		Discover: func() (DockerEndpoint, error) {
+			var paths []string
+			if dir := runtimeDir(); dir != "" {
+				paths = append(paths, filepath.Join(dir, "podman", "podman.sock"))
+			}
+			paths = append(paths,
+				"/run/podman/podman.sock",
+				filepath.Join(".local", "share", "containers", "podman", "machine", "podman.sock"),
+			)
+
+			return discoverSocket(paths...)
+		},
+	}
+}
+
+// runtimeDir returns the user's runtime directory: XDG_RUNTIME_DIR,
+// or /run/user/<uid> on platforms with user IDs.
+func runtimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir
+	}
+
+	if uid := os.Getuid(); uid >= 0 {
+		return filepath.Join("/run", "user", strconv.Itoa(uid))
+	}
+
+	return ""
+}
+
+// discoverSocket returns the Docker endpoint of the first usable socket of the given paths.
+// Relative paths are resolved from the user's home directory.
+func discoverSocket(paths ...string) (DockerEndpoint, error) {
+	var home string
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			if home == "" {
+				var err error
+				if home, err = os.UserHomeDir(); err != nil {
+					return DockerEndpoint{}, fmt.Errorf("home dir: %w", ErrHostNotDiscovered)
+				}
+			}
+			path = filepath.Join(home, path)
+		}
+
+		host := "unix://" + path
+		if checkSocket(host) == nil {
+			return DockerEndpoint{Host: host}, nil
+		}
+	}
+
+	return DockerEndpoint{}, fmt.Errorf("no usable socket: %w", ErrHostNotDiscovered)
+}
+
+// checkSocket checks that the socket of the given Docker host accepts connections,
+// returning an error wrapping [ErrHostNotDiscovered] otherwise.
+func checkSocket(host string) error {
+	h, err := ParseHost(false, host)
+	if err != nil {
+		return fmt.Errorf("%w: %w", err, ErrHostNotDiscovered)
+	}
+	proto, addr := h.Proto, h.Addr
+
+	var conn net.Conn
+	switch proto {
+	case "unix":
+		fi, err := os.Stat(addr)
+		if err != nil {
+			return fmt.Errorf("stat socket: %w: %w", err, ErrHostNotDiscovered)
+		}
+		if fi.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%q is not a socket: %w", addr, ErrHostNotDiscovered)
+		}
+
+		conn, err = net.DialTimeout(proto, addr, socketDialTimeout)
+		if err != nil {
+			return fmt.Errorf("dial socket: %w: %w", err, ErrHostNotDiscovered)
+		}
+	case "npipe":
+		conn, err = dialPipe(context.Background(), addr, socketDialTimeout)
+		if err != nil {
+			return fmt.Errorf("dial pipe: %w: %w", err, ErrHostNotDiscovered)
+		}
+	default:
+		return fmt.Errorf("unsupported protocol %q: %w", proto, ErrHostNotDiscovered)
+	}
+
+	return conn.Close()
+}
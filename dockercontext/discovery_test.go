@@ -0,0 +1,250 @@
+package dockercontext
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverDockerHost(t *testing.T) {
+	t.Run("env", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+		setupOverrides(tt, "tcp://127.0.0.1:1234", "")
+
+		discovery, err := DiscoverDockerHost()
+		require.NoError(tt, err)
+		require.Equal(tt, Discovery{
+			Strategy: "env",
+			Endpoint: DockerEndpoint{ContextName: DefaultContextName, Host: "tcp://127.0.0.1:1234"},
+		}, discovery)
+	})
+
+	t.Run("env/tls", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+		setupOverrides(tt, "", "")
+		tt.Setenv(EnvTLSVerify, "")
+		tt.Setenv(EnvEnableTLS, "1")
+		tt.Setenv(EnvOverrideCertPath, tt.TempDir())
+
+		discovery, err := DiscoverDockerHost()
+		require.NoError(tt, err)
+		require.Equal(tt, "env", discovery.Strategy)
+		require.Equal(tt, DefaultTLSHost, discovery.Endpoint.Host)
+		require.NotNil(tt, discovery.Endpoint.TLSConfig)
+		require.True(tt, discovery.Endpoint.TLSConfig.InsecureSkipVerify)
+	})
+
+	t.Run("context", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+		setupOverrides(tt, "", "")
+		tt.Setenv(EnvTLSVerify, "")
+
+		discovery, err := DiscoverDockerHost()
+		require.NoError(tt, err)
+		require.Equal(tt, Discovery{
+			Strategy: "context",
+			Endpoint: DockerEndpoint{ContextName: "context1", Host: "tcp://127.0.0.1:1"},
+		}, discovery)
+	})
+
+	t.Run("context/not-found", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+		setupOverrides(tt, "", "context-not-found")
+		tt.Setenv(EnvTLSVerify, "")
+
+		_, err := DiscoverDockerHost()
+		require.ErrorIs(tt, err, ErrContextNotFound)
+		require.ErrorContains(tt, err, `strategy "context"`)
+	})
+
+	t.Run("sockets", func(tt *testing.T) {
+		setupSocketDiscovery(tt)
+
+		home, err := os.UserHomeDir()
+		require.NoError(tt, err)
+
+		rancher := listenSocket(tt, filepath.Join(home, ".rd", "docker.sock"))
+
+		discovery, err := DiscoverDockerHost(
+			EnvStrategy(),
+			ContextStrategy(),
+			RootlessStrategy(),
+			DockerDesktopStrategy(),
+			ColimaStrategy(""),
+			RancherDesktopStrategy(),
+		)
+		require.NoError(tt, err)
+		require.Equal(tt, Discovery{
+			Strategy: "rancher-desktop",
+			Endpoint: DockerEndpoint{Host: "unix://" + rancher},
+		}, discovery)
+	})
+
+	t.Run("sockets/order", func(tt *testing.T) {
+		runtimeDir := setupSocketDiscovery(tt)
+
+		home, err := os.UserHomeDir()
+		require.NoError(tt, err)
+
+		rootless := listenSocket(tt, filepath.Join(runtimeDir, "docker.sock"))
+		listenSocket(tt, filepath.Join(home, ".docker", "run", "docker.sock"))
+
+		discovery, err := DiscoverDockerHost(RootlessStrategy(), DockerDesktopStrategy())
+		require.NoError(tt, err)
+		require.Equal(tt, Discovery{
+			Strategy: "rootless",
+			Endpoint: DockerEndpoint{Host: "unix://" + rootless},
+		}, discovery)
+	})
+
+	t.Run("sockets/colima-profile", func(tt *testing.T) {
+		setupSocketDiscovery(tt)
+		tt.Setenv(EnvColimaProfile, "work")
+
+		home, err := os.UserHomeDir()
+		require.NoError(tt, err)
+
+		listenSocket(tt, filepath.Join(home, ".colima", "default", "docker.sock"))
+		work := listenSocket(tt, filepath.Join(home, ".colima", "work", "docker.sock"))
+
+		discovery, err := DiscoverDockerHost(ColimaStrategy(""))
+		require.NoError(tt, err)
+		require.Equal(tt, "unix://"+work, discovery.Endpoint.Host)
+
+		discovery, err = DiscoverDockerHost(ColimaStrategy("default"))
+		require.NoError(tt, err)
+		require.Equal(tt, "unix://"+filepath.Join(home, ".colima", "default", "docker.sock"), discovery.Endpoint.Host)
+	})
+
+	t.Run("sockets/podman", func(tt *testing.T) {
+		runtimeDir := setupSocketDiscovery(tt)
+
+		podman := listenSocket(tt, filepath.Join(runtimeDir, "podman", "podman.sock"))
+
+		discovery, err := DiscoverDockerHost(PodmanStrategy())
+		require.NoError(tt, err)
+		require.Equal(tt, Discovery{
+			Strategy: "podman",
+			Endpoint: DockerEndpoint{Host: "unix://" + podman},
+		}, discovery)
+	})
+
+	t.Run("sockets/skip-unusable", func(tt *testing.T) {
+		setupSocketDiscovery(tt)
+
+		home, err := os.UserHomeDir()
+		require.NoError(tt, err)
+
+		// a regular file is not a socket
+		notSocket := filepath.Join(home, "not-a-socket")
+		require.NoError(tt, os.WriteFile(notSocket, nil, 0o600))
+
+		// a socket nobody is listening on refuses connections
+		stale := staleSocket(tt, filepath.Join(home, "stale.sock"))
+
+		usable := listenSocket(tt, filepath.Join(home, "usable.sock"))
+
+		discovery, err := DiscoverDockerHost(
+			SocketStrategy("missing", filepath.Join(home, "missing.sock")),
+			SocketStrategy("custom", notSocket, stale, "usable.sock"),
+		)
+		require.NoError(tt, err)
+		require.Equal(tt, Discovery{
+			Strategy: "custom",
+			Endpoint: DockerEndpoint{Host: "unix://" + usable},
+		}, discovery)
+	})
+
+	t.Run("not-discovered", func(tt *testing.T) {
+		setupSocketDiscovery(tt)
+
+		_, err := DiscoverDockerHost(EnvStrategy(), ContextStrategy(), RootlessStrategy(), DockerDesktopStrategy())
+		require.ErrorIs(tt, err, ErrHostNotDiscovered)
+	})
+
+	t.Run("custom-error", func(tt *testing.T) {
+		errCustom := errors.New("custom error")
+
+		_, err := DiscoverDockerHost(HostStrategy{
+			Name: "custom",
+			Discover: func() (DockerEndpoint, error) {
+				return DockerEndpoint{}, errCustom
+			},
+		})
+		require.ErrorIs(tt, err, errCustom)
+	})
+}
+
+// setupSocketDiscovery sets up an empty home and runtime directory, and clears the
+// environment variables selecting the host or the context, so only sockets are discovered.
+// It returns the runtime directory.
+func setupSocketDiscovery(t *testing.T) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not discovered on Windows")
+	}
+
+	// the socket paths must fit in the length limit of unix sockets, so they are kept short
+	home, err := os.MkdirTemp("", "home")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(home))
+	})
+
+	t.Setenv("HOME", home)
+	setupOverrides(t, "", "")
+	t.Setenv(EnvTLSVerify, "")
+	t.Setenv(EnvEnableTLS, "")
+	t.Setenv(EnvColimaProfile, "")
+
+	runtimeDir := filepath.Join(home, "run")
+	tempMkdirAll(t, runtimeDir)
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+
+	return runtimeDir
+}
+
+// listenSocket listens on a unix socket in the given path, accepting and
+// closing connections until the test ends. It returns the path.
+func listenSocket(t *testing.T, path string) string {
+	t.Helper()
+
+	tempMkdirAll(t, filepath.Dir(path))
+
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		l.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	return path
+}
+
+// staleSocket creates a unix socket in the given path nobody is listening on,
+// as left behind by a stopped daemon. It returns the path.
+func staleSocket(t *testing.T, path string) string {
+	t.Helper()
+
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	require.NoError(t, err)
+	l.SetUnlinkOnClose(false)
+	require.NoError(t, l.Close())
+
+	return path
+}
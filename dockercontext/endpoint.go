@@ -0,0 +1,63 @@
+package dockercontext
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// DockerEndpoint is the Docker endpoint of a context, resolved with its TLS configuration,
+// so it's ready to connect to.
+type DockerEndpoint struct {
+	// ContextName is the name of the context the endpoint belongs to.
+	ContextName string
+
+	// Host is the address of the Docker daemon, e.g. "unix:///var/run/docker.sock".
+	Host string
+
+	// TLSConfig is the TLS configuration to connect to the Docker daemon.
+	// It's nil when the endpoint doesn't use TLS.
+	TLSConfig *tls.Config
+}
+
+// ResolveDockerEndpoint resolves the Docker endpoint of the context with the given name.
+// The default context is resolved from the environment, as the docker CLI does.
+//
+// It returns [ErrContextNotFound] if there is no such context, [ErrDockerHostNotSet]
+// if it has no Docker host, and an error if the host is not valid, see [ParseHost].
+func ResolveDockerEndpoint(name string) (DockerEndpoint, error) {
+	ctx, err := Inspect(name)
+	if err != nil {
+		return DockerEndpoint{}, err
+	}
+
+	ep, ok := ctx.Endpoints[dockerEndpoint]
+	if !ok || ep.Host == "" {
+		return DockerEndpoint{}, ErrDockerHostNotSet
+	}
+
+	tlsConfig, err := endpointTLSConfig(ctx, dockerEndpoint)
+	if err != nil {
+		return DockerEndpoint{}, fmt.Errorf("tls config: %w", err)
+	}
+
+	if _, err := ParseHost(tlsConfig != nil, ep.Host); err != nil {
+		return DockerEndpoint{}, err
+	}
+
+	return DockerEndpoint{
+		ContextName: ctx.Name,
+		Host:        ep.Host,
+		TLSConfig:   tlsConfig,
+	}, nil
+}
+
+// CurrentDockerEndpoint resolves the Docker endpoint of the current context.
+// See [Current] and [ResolveDockerEndpoint] for details.
+func CurrentDockerEndpoint() (DockerEndpoint, error) {
+	current, err := Current()
+	if err != nil {
+		return DockerEndpoint{}, fmt.Errorf("current context: %w", err)
+	}
+
+	return ResolveDockerEndpoint(current)
+}
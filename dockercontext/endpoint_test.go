@@ -0,0 +1,77 @@
+package dockercontext
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDockerEndpoint(t *testing.T) {
+	t.Run("context", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		ep, err := ResolveDockerEndpoint("context1")
+		require.NoError(tt, err)
+		require.Equal(tt, DockerEndpoint{ContextName: "context1", Host: "tcp://127.0.0.1:1"}, ep)
+	})
+
+	t.Run("context/tls", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		ctx, err := Inspect("context1")
+		require.NoError(tt, err)
+		writeCertificates(tt, filepath.Join(ctx.Storage.TLSPath, "docker"))
+
+		ep, err := ResolveDockerEndpoint("context1")
+		require.NoError(tt, err)
+		require.NotNil(tt, ep.TLSConfig)
+		require.Len(tt, ep.TLSConfig.Certificates, 1)
+	})
+
+	t.Run("default/tls", func(tt *testing.T) {
+		certPath := tt.TempDir()
+		writeCertificates(tt, certPath)
+
+		tt.Setenv(EnvOverrideHost, "")
+		tt.Setenv(EnvTLSVerify, "1")
+		tt.Setenv(EnvOverrideCertPath, certPath)
+
+		ep, err := ResolveDockerEndpoint(DefaultContextName)
+		require.NoError(tt, err)
+		require.Equal(tt, DefaultContextName, ep.ContextName)
+		require.Equal(tt, DefaultTLSHost, ep.Host)
+		require.NotNil(tt, ep.TLSConfig)
+	})
+
+	t.Run("host-not-set", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		_, err := ResolveDockerEndpoint("context2") // the context with no host
+		require.ErrorIs(tt, err, ErrDockerHostNotSet)
+	})
+
+	t.Run("invalid-host", func(tt *testing.T) {
+		tt.Setenv(EnvOverrideHost, "tpc://127.0.0.1:2375")
+
+		_, err := ResolveDockerEndpoint(DefaultContextName)
+		require.ErrorContains(tt, err, `invalid docker host "tpc://127.0.0.1:2375": unsupported protocol "tpc"`)
+	})
+
+	t.Run("not-found", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		_, err := ResolveDockerEndpoint("context-not-found")
+		require.ErrorIs(tt, err, ErrContextNotFound)
+	})
+}
+
+func TestCurrentDockerEndpoint(t *testing.T) {
+	setupDockerContexts(t, 1, 1)
+	setupOverrides(t, "", "")
+
+	ep, err := CurrentDockerEndpoint()
+	require.NoError(t, err)
+	require.Equal(t, "context1", ep.ContextName)
+	require.Equal(t, "tcp://127.0.0.1:1", ep.Host)
+}
@@ -0,0 +1,210 @@
+package dockercontext
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/mdelapenya/docker-sdk-go/dockercontext/internal"
+)
+
+// kubernetesEndpoint is the name of the endpoint to connect to a Kubernetes cluster
+const kubernetesEndpoint = "kubernetes"
+
+// KubernetesEndpoint is the typed view of the "kubernetes" endpoint of a context,
+// as stored by the docker CLI.
+type KubernetesEndpoint struct {
+	Host             string `json:",omitempty"`
+	SkipTLSVerify    bool
+	DefaultNamespace string `json:",omitempty"`
+	KubeconfigFile   string `json:",omitempty"`
+}
+
+// EndpointDecoder decodes the raw JSON of an endpoint into a typed value.
+type EndpointDecoder func(raw json.RawMessage) (any, error)
+
+//nolint:gochecknoglobals // Endpoint types are registered process wide, like database/sql drivers.
+var (
+	endpointDecodersMu sync.RWMutex
+	endpointDecoders   = map[string]EndpointDecoder{
+		dockerEndpoint:     decodeEndpoint,
+		kubernetesEndpoint: decodeEndpointAs[KubernetesEndpoint],
+	}
+)
+
+// RegisterEndpointDecoder registers the decoder of the endpoints of the given type,
+// used by [DecodeEndpointAny]. The "docker" and "kubernetes" endpoint types are
+// registered by default, decoding into [Endpoint] and [KubernetesEndpoint] respectively.
+func RegisterEndpointDecoder(endpointType string, dec EndpointDecoder) {
+	endpointDecodersMu.Lock()
+	defer endpointDecodersMu.Unlock()
+
+	endpointDecoders[endpointType] = dec
+}
+
+// RegisterEndpointType registers T as the type of the endpoints of the given type,
+// so [DecodeEndpointAny] decodes them into a value of type T.
+func RegisterEndpointType[T any](endpointType string) {
+	RegisterEndpointDecoder(endpointType, decodeEndpointAs[T])
+}
+
+// decodeEndpointAs decodes the raw JSON of an endpoint into a value of type T.
+func decodeEndpointAs[T any](raw json.RawMessage) (any, error) {
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// DecodeEndpointAny decodes the endpoint with the given name using the decoder registered
+// for its type. Endpoints without a registered decoder are returned as an [Endpoint].
+func DecodeEndpointAny(ctx Context, name string) (any, error) {
+	endpointDecodersMu.RLock()
+	dec, ok := endpointDecoders[name]
+	endpointDecodersMu.RUnlock()
+
+	if !ok {
+		ep, found := ctx.Endpoints[name]
+		if !found {
+			return nil, fmt.Errorf("context %q: endpoint %q not found", ctx.Name, name)
+		}
+		return ep, nil
+	}
+
+	raw, err := EndpointJSON(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := dec(raw)
+	if err != nil {
+		return nil, fmt.Errorf("decode endpoint %q: %w", name, err)
+	}
+	return v, nil
+}
+
+// EndpointJSON returns the raw JSON of the endpoint with the given name, as stored in meta.json.
+func EndpointJSON(ctx Context, name string) (json.RawMessage, error) {
+	ep, ok := ctx.Endpoints[name]
+	if !ok {
+		return nil, fmt.Errorf("context %q: endpoint %q not found", ctx.Name, name)
+	}
+
+	raw, err := internal.EndpointJSON(ep)
+	if err != nil {
+		return nil, fmt.Errorf("encode endpoint %q: %w", name, err)
+	}
+	return raw, nil
+}
+
+// DecodeEndpoint decodes the raw JSON of the endpoint with the given name into a value of type T.
+func DecodeEndpoint[T any](ctx Context, name string) (T, error) {
+	var v T
+
+	raw, err := EndpointJSON(ctx, name)
+	if err != nil {
+		return v, err
+	}
+
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return v, fmt.Errorf("decode endpoint %q: %w", name, err)
+	}
+	return v, nil
+}
+
+// SetEndpoint encodes v as the endpoint with the given name. The keys of its JSON
+// encoding are merged into the existing endpoint, so the keys unknown to v survive
+// a read-modify-write with [DecodeEndpoint].
+func SetEndpoint(ctx *Context, name string, v any) error {
+	merged, err := mergeEndpointJSON(ctx.Endpoints[name], v)
+	if err != nil {
+		return fmt.Errorf("encode endpoint %q: %w", name, err)
+	}
+
+	ep, err := internal.EndpointFromJSON(merged)
+	if err != nil {
+		return fmt.Errorf("decode endpoint %q: %w", name, err)
+	}
+
+	if ctx.Endpoints == nil {
+		ctx.Endpoints = make(map[string]Endpoint)
+	}
+	ctx.Endpoints[name] = ep
+	return nil
+}
+
+// DecodeFields decodes the additional metadata fields of the context into a value of type T.
+func DecodeFields[T any](ctx Context) (T, error) {
+	var v T
+
+	raw, err := json.Marshal(ctx.Fields)
+	if err != nil {
+		return v, fmt.Errorf("encode fields: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return v, fmt.Errorf("decode fields: %w", err)
+	}
+	return v, nil
+}
+
+// SetFields encodes v as additional metadata fields of the context. The keys of
+// its JSON encoding are merged into the existing fields, so the keys unknown to v
+// survive a read-modify-write with [DecodeFields].
+func SetFields(ctx *Context, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode fields: %w", err)
+	}
+
+	// the numbers are kept as json.Number, so large integers are not rounded
+	var fields map[string]any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return fmt.Errorf("decode fields: %w", err)
+	}
+
+	if ctx.Fields == nil {
+		ctx.Fields = make(map[string]any, len(fields))
+	}
+	maps.Copy(ctx.Fields, fields)
+	return nil
+}
+
+// mergeEndpointJSON returns the JSON encoding of the endpoint, with the keys of the
+// JSON encoding of v merged into it.
+func mergeEndpointJSON(ep Endpoint, v any) ([]byte, error) {
+	raw, err := internal.EndpointJSON(ep)
+	if err != nil {
+		return nil, err
+	}
+
+	// the values are merged as raw JSON, so they are written back unchanged
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &merged); err != nil {
+		return nil, err
+	}
+
+	raw, err = json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+	maps.Copy(merged, values)
+
+	return json.Marshal(merged)
+}
+
+// decodeEndpoint decodes the raw JSON of an endpoint into an [Endpoint],
+// keeping its additional fields.
+func decodeEndpoint(raw json.RawMessage) (any, error) {
+	return internal.EndpointFromJSON(raw)
+}
@@ -0,0 +1,232 @@
+package dockercontext
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// desktopFields are the additional metadata fields set by a desktop tool, for testing
+type desktopFields struct {
+	Owner   string `json:"owner"`
+	Managed bool   `json:"managed"`
+}
+
+// builderEndpoint is a custom endpoint type, for testing
+type builderEndpoint struct {
+	Host      string
+	Platforms []string `json:"platforms"`
+}
+
+func TestDecodeFields(t *testing.T) {
+	t.Run("success", func(tt *testing.T) {
+		ctx := Context{Fields: map[string]any{"owner": "me", "managed": true, "other": 1}}
+
+		fields, err := DecodeFields[desktopFields](ctx)
+		require.NoError(tt, err)
+		require.Equal(tt, desktopFields{Owner: "me", Managed: true}, fields)
+	})
+
+	t.Run("no-fields", func(tt *testing.T) {
+		fields, err := DecodeFields[desktopFields](Context{})
+		require.NoError(tt, err)
+		require.Empty(tt, fields)
+	})
+
+	t.Run("invalid-type", func(tt *testing.T) {
+		_, err := DecodeFields[desktopFields](Context{Fields: map[string]any{"owner": 1}})
+		require.ErrorContains(tt, err, "decode fields")
+	})
+}
+
+func TestSetFields(t *testing.T) {
+	ctx := Context{Fields: map[string]any{"owner": "me", "other": "kept"}}
+
+	fields, err := DecodeFields[desktopFields](ctx)
+	require.NoError(t, err)
+
+	fields.Owner = "you"
+	fields.Managed = true
+	require.NoError(t, SetFields(&ctx, fields))
+
+	require.Equal(t, map[string]any{"owner": "you", "managed": true, "other": "kept"}, ctx.Fields)
+
+	t.Run("nil-fields", func(tt *testing.T) {
+		var ctx Context
+		require.NoError(tt, SetFields(&ctx, desktopFields{Owner: "me"}))
+		require.Equal(tt, map[string]any{"owner": "me", "managed": false}, ctx.Fields)
+	})
+
+	t.Run("large-integer", func(tt *testing.T) {
+		var ctx Context
+		require.NoError(tt, SetFields(&ctx, struct{ ID uint64 }{ID: 1<<53 + 1}))
+		require.Equal(tt, map[string]any{"ID": json.Number("9007199254740993")}, ctx.Fields)
+	})
+}
+
+func TestDecodeEndpoint(t *testing.T) {
+	ctx := Context{
+		Name: "remote",
+		Endpoints: map[string]Endpoint{
+			"kubernetes": {Host: "https://1.2.3.4:6443", Fields: map[string]any{"DefaultNamespace": "dev"}},
+			"builder":    {Host: "tcp://1.2.3.4:1234", Fields: map[string]any{"platforms": []any{"linux/amd64"}}},
+		},
+	}
+
+	t.Run("kubernetes", func(tt *testing.T) {
+		ep, err := DecodeEndpoint[KubernetesEndpoint](ctx, "kubernetes")
+		require.NoError(tt, err)
+		require.Equal(tt, KubernetesEndpoint{Host: "https://1.2.3.4:6443", DefaultNamespace: "dev"}, ep)
+	})
+
+	t.Run("custom", func(tt *testing.T) {
+		ep, err := DecodeEndpoint[builderEndpoint](ctx, "builder")
+		require.NoError(tt, err)
+		require.Equal(tt, builderEndpoint{Host: "tcp://1.2.3.4:1234", Platforms: []string{"linux/amd64"}}, ep)
+	})
+
+	t.Run("raw-json", func(tt *testing.T) {
+		raw, err := EndpointJSON(ctx, "builder")
+		require.NoError(tt, err)
+		require.JSONEq(tt, `{"Host":"tcp://1.2.3.4:1234","SkipTLSVerify":false,"platforms":["linux/amd64"]}`, string(raw))
+	})
+
+	t.Run("not-found", func(tt *testing.T) {
+		_, err := DecodeEndpoint[builderEndpoint](ctx, "other")
+		require.ErrorContains(tt, err, `endpoint "other" not found`)
+	})
+}
+
+func TestSetEndpoint(t *testing.T) {
+	t.Run("keeps-unknown-keys", func(tt *testing.T) {
+		ctx := Context{
+			Endpoints: map[string]Endpoint{
+				"builder": {Host: "tcp://1.2.3.4:1234", SkipTLSVerify: true, Fields: map[string]any{"platforms": []any{"linux/amd64"}, "token": "abc"}},
+			},
+		}
+
+		ep, err := DecodeEndpoint[builderEndpoint](ctx, "builder")
+		require.NoError(tt, err)
+
+		ep.Host = "tcp://5.6.7.8:1234"
+		ep.Platforms = append(ep.Platforms, "linux/arm64")
+		require.NoError(tt, SetEndpoint(&ctx, "builder", ep))
+
+		require.Equal(tt, Endpoint{
+			Host:          "tcp://5.6.7.8:1234",
+			SkipTLSVerify: true,
+			Fields:        map[string]any{"platforms": []any{"linux/amd64", "linux/arm64"}, "token": "abc"},
+		}, ctx.Endpoints["builder"])
+	})
+
+	t.Run("keeps-large-integers", func(tt *testing.T) {
+		ctx := Context{
+			Endpoints: map[string]Endpoint{
+				"builder": {Host: "tcp://1.2.3.4:1234", Fields: map[string]any{"id": json.Number("9007199254740993")}},
+			},
+		}
+
+		require.NoError(tt, SetEndpoint(&ctx, "builder", builderEndpoint{Host: "tcp://5.6.7.8:1234"}))
+		require.Equal(tt, json.Number("9007199254740993"), ctx.Endpoints["builder"].Fields["id"])
+	})
+
+	t.Run("new-endpoint", func(tt *testing.T) {
+		var ctx Context
+		require.NoError(tt, SetEndpoint(&ctx, "kubernetes", KubernetesEndpoint{Host: "https://1.2.3.4:6443", DefaultNamespace: "dev"}))
+
+		require.Equal(tt, Endpoint{Host: "https://1.2.3.4:6443", Fields: map[string]any{"DefaultNamespace": "dev"}}, ctx.Endpoints["kubernetes"])
+	})
+
+	t.Run("read-modify-write", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		ctx := Context{
+			Name: "remote",
+			Endpoints: map[string]Endpoint{
+				"builder": {Host: "tcp://1.2.3.4:1234", Fields: map[string]any{"token": "abc"}},
+			},
+		}
+		require.NoError(tt, Create(ctx))
+
+		ctx, err := Inspect("remote")
+		require.NoError(tt, err)
+
+		ep, err := DecodeEndpoint[builderEndpoint](ctx, "builder")
+		require.NoError(tt, err)
+		ep.Platforms = []string{"linux/amd64"}
+		require.NoError(tt, SetEndpoint(&ctx, "builder", ep))
+		require.NoError(tt, Update(ctx))
+
+		ctx, err = Inspect("remote")
+		require.NoError(tt, err)
+
+		raw, err := EndpointJSON(ctx, "builder")
+		require.NoError(tt, err)
+		require.JSONEq(tt, `{"Host":"tcp://1.2.3.4:1234","SkipTLSVerify":false,"platforms":["linux/amd64"],"token":"abc"}`, string(raw))
+	})
+}
+
+func TestDecodeEndpointAny(t *testing.T) {
+	ctx := Context{
+		Name: "remote",
+		Endpoints: map[string]Endpoint{
+			"docker":     {Host: "tcp://1.2.3.4:2375", Fields: map[string]any{"extra": "kept"}},
+			"kubernetes": {Host: "https://1.2.3.4:6443", Fields: map[string]any{"DefaultNamespace": "dev"}},
+			"builder":    {Host: "tcp://1.2.3.4:1234", Fields: map[string]any{"platforms": []any{"linux/amd64"}}},
+			"unknown":    {Host: "tcp://1.2.3.4:4321"},
+		},
+	}
+
+	t.Run("docker", func(tt *testing.T) {
+		ep, err := DecodeEndpointAny(ctx, "docker")
+		require.NoError(tt, err)
+		require.Equal(tt, Endpoint{Host: "tcp://1.2.3.4:2375", Fields: map[string]any{"extra": "kept"}}, ep)
+	})
+
+	t.Run("kubernetes", func(tt *testing.T) {
+		ep, err := DecodeEndpointAny(ctx, "kubernetes")
+		require.NoError(tt, err)
+		require.Equal(tt, KubernetesEndpoint{Host: "https://1.2.3.4:6443", DefaultNamespace: "dev"}, ep)
+	})
+
+	t.Run("registered", func(tt *testing.T) {
+		RegisterEndpointType[builderEndpoint]("builder")
+		tt.Cleanup(func() {
+			endpointDecodersMu.Lock()
+			delete(endpointDecoders, "builder")
+			endpointDecodersMu.Unlock()
+		})
+
+		ep, err := DecodeEndpointAny(ctx, "builder")
+		require.NoError(tt, err)
+		require.Equal(tt, builderEndpoint{Host: "tcp://1.2.3.4:1234", Platforms: []string{"linux/amd64"}}, ep)
+	})
+
+	t.Run("decoder-error", func(tt *testing.T) {
+		errDecode := errors.New("decode error")
+		RegisterEndpointDecoder("builder", func(_ json.RawMessage) (any, error) {
+			return nil, errDecode
+		})
+		tt.Cleanup(func() {
+			endpointDecodersMu.Lock()
+			delete(endpointDecoders, "builder")
+			endpointDecodersMu.Unlock()
+		})
+
+		_, err := DecodeEndpointAny(ctx, "builder")
+		require.ErrorIs(tt, err, errDecode)
+	})
+
+	t.Run("unregistered", func(tt *testing.T) {
+		ep, err := DecodeEndpointAny(ctx, "unknown")
+		require.NoError(tt, err)
+		require.Equal(tt, Endpoint{Host: "tcp://1.2.3.4:4321"}, ep)
+	})
+
+	t.Run("not-found", func(tt *testing.T) {
+		_, err := DecodeEndpointAny(ctx, "other")
+		require.ErrorContains(tt, err, `endpoint "other" not found`)
+	})
+}
@@ -0,0 +1,185 @@
+package dockercontext
+
+// The parsing rules in this file follow the ones of https://github.com/docker/cli,
+// more especifically https://github.com/docker/cli/blob/master/opts/hosts.go
+// and https://github.com/docker/cli/blob/master/cli/connhelper/ssh/ssh.go
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultHTTPPort is the default port of a "tcp://" Docker host without TLS.
+	DefaultHTTPPort = 2375
+
+	// DefaultTLSHTTPPort is the default port of a "tcp://" Docker host with TLS.
+	DefaultTLSHTTPPort = 2376
+
+	// defaultHTTPHost is the default address of a "tcp://" Docker host
+	defaultHTTPHost = "localhost"
+
+	// defaultUnixSocket is the default address of a "unix://" Docker host
+	defaultUnixSocket = "/var/run/docker.sock"
+
+	// defaultNamedPipe is the default address of a "npipe://" Docker host
+	defaultNamedPipe = "//./pipe/docker_engine"
+)
+
+// Host is a Docker host, validated and normalized by [ParseHost].
+type Host struct {
+	// Proto is the protocol of the host: "unix", "tcp", "ssh", "npipe" or "fd".
+	Proto string
+
+	// Addr is the address of the host, which depends on the protocol: the path of the socket
+	// or named pipe, "host:port" for "tcp", "[user@]host[:port]" for "ssh", and the file
+	// descriptors for "fd".
+	Addr string
+
+	// BasePath is the path prefix of the requests to a "tcp" host, without the trailing slash,
+	// or the path of the socket of the remote Docker daemon of a "ssh" host.
+	BasePath string
+}
+
+// String returns the normalized form of the host, e.g. "tcp://localhost:2375".
+func (h Host) String() string {
+	return h.Proto + "://" + h.Addr + h.BasePath
+}
+
+// ParseHost validates and normalizes a Docker host, like the DOCKER_HOST environment variable
+// or the host of a context, with the same rules as the docker CLI:
+//
+//   - An empty host is the default one: [DefaultTLSHost] if defaultToTLS is set,
+//     or the platform's [DefaultDockerHost] otherwise.
+//   - A host without a protocol, e.g. "1.2.3.4:2375", is a "tcp" one.
+//   - A "tcp" host gets "localhost" as default address, and [DefaultTLSHTTPPort] as default
+//     port if defaultToTLS is set, or [DefaultHTTPPort] otherwise. It can have a base path,
+//     but no credentials, query nor fragment.
+//   - A "unix" or "npipe" host gets the default socket or named pipe as default address.
+//   - A "ssh" host must have a host name, and can have a user, a port and the path of
+//     the remote socket, but no password, query nor fragment.
+//   - A "fd" host is kept as is, as it's only meaningful to the Docker daemon.
+//
+// Any other protocol is rejected, so typos are reported before trying to connect.
+func ParseHost(defaultToTLS bool, val string) (Host, error) {
+	host := strings.TrimSpace(val)
+	if host == "" {
+		host = DefaultDockerHost
+		if defaultToTLS {
+			host = DefaultTLSHost
+		}
+	}
+
+	proto, addr, ok := strings.Cut(host, "://")
+	if !ok {
+		if strings.Contains(host, "/") {
+			return Host{}, fmt.Errorf("invalid docker host %q: missing protocol, e.g. unix:// or tcp://", val)
+		}
+		proto, addr = "tcp", host
+	}
+
+	var (
+		h   Host
+		err error
+	)
+	switch proto {
+	case "tcp":
+		h, err = parseTCPHost(addr, defaultToTLS)
+	case "unix":
+		h, err = parseSimpleHost(proto, addr, defaultUnixSocket)
+	case "npipe":
+		h, err = parseSimpleHost(proto, addr, defaultNamedPipe)
+	case "ssh":
+		h, err = parseSSHHost(host)
+	case "fd":
+		h = Host{Proto: proto, Addr: addr}
+	default:
+		return Host{}, fmt.Errorf("invalid docker host %q: unsupported protocol %q", val, proto)
+	}
+	if err != nil {
+		return Host{}, fmt.Errorf("invalid docker host %q: %w", val, err)
+	}
+
+	return h, nil
+}
+
+// parseSimpleHost parses the address of a host whose address is a path.
+func parseSimpleHost(proto, addr, defaultAddr string) (Host, error) {
+	if strings.Contains(addr, "://") {
+		return Host{}, fmt.Errorf("invalid protocol, expected %s", proto)
+	}
+
+	if addr == "" {
+		addr = defaultAddr
+	}
+
+	return Host{Proto: proto, Addr: addr}, nil
+}
+
+// parseTCPHost parses the address of a "tcp" host, filling in the default address and port.
+func parseTCPHost(addr string, defaultToTLS bool) (Host, error) {
+	if strings.Contains(addr, "://") {
+		return Host{}, errors.New("invalid protocol, expected tcp")
+	}
+
+	defaultPort := strconv.Itoa(DefaultHTTPPort)
+	if defaultToTLS {
+		defaultPort = strconv.Itoa(DefaultTLSHTTPPort)
+	}
+
+	// url.Parse fails on an IPv6 address with an empty port, e.g. "[::1]:"
+	if strings.HasSuffix(addr, "]:") {
+		addr += defaultPort
+	}
+
+	u, err := url.Parse("tcp://" + addr)
+	if err != nil {
+		return Host{}, err
+	}
+
+	switch {
+	case u.User != nil:
+		return Host{}, errors.New("credentials are not supported")
+	case u.RawQuery != "" || u.ForceQuery:
+		return Host{}, fmt.Errorf("query parameters are not supported: %q", u.RawQuery)
+	case u.Fragment != "":
+		return Host{}, fmt.Errorf("fragments are not supported: %q", u.Fragment)
+	}
+
+	hostname, port := u.Hostname(), u.Port()
+	if hostname == "" {
+		hostname = defaultHTTPHost
+	}
+	if port == "" {
+		port = defaultPort
+	}
+
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return Host{}, fmt.Errorf("invalid port %q", port)
+	}
+
+	return Host{
+		Proto:    "tcp",
+		Addr:     net.JoinHostPort(hostname, port),
+		BasePath: strings.TrimSuffix(u.Path, "/"),
+	}, nil
+}
+
+// parseSSHHost parses a "ssh://[user@]host[:port][/socket]" host.
+func parseSSHHost(host string) (Host, error) {
+	u, err := parseSSHURL(host)
+	if err != nil {
+		return Host{}, err
+	}
+
+	addr := u.Host
+	if u.User != nil {
+		addr = u.User.Username() + "@" + addr
+	}
+
+	return Host{Proto: "ssh", Addr: addr, BasePath: u.Path}, nil
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package dockercontext
+
+// DefaultDockerHost is the default host to connect to the Docker daemon,
+// used when no context nor the DOCKER_HOST environment variable is set.
+const DefaultDockerHost = "unix:///var/run/docker.sock"
@@ -0,0 +1,275 @@
+package internal
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxImportFileSize is the maximum size of an imported archive, and so of each of its
+// files, as enforced by the docker CLI.
+const maxImportFileSize = 10 << 20
+
+// archiveTLSDir is the name of the directory holding the TLS material in an archive
+const archiveTLSDir = "tls"
+
+// Export writes the context with the given name as a tar archive, in the format of
+// "docker context export": its meta.json file, followed by a "tls" directory with
+// a subdirectory for each endpoint holding its TLS files. As in the docker CLI, the
+// "tls" directory is only written if the context has TLS files.
+// It returns [ErrContextNotFound] if there is no such context.
+func Export(contextName string, metaRoot string, w io.Writer) error {
+	meta, err := find(contextName, metaRoot)
+	if err != nil {
+		return err
+	}
+
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("encode metadata: %w", err)
+	}
+
+	tlsFiles, err := listTLSFiles(tlsDir(contextName, metaRoot))
+	if err != nil {
+		return fmt.Errorf("list tls files: %w", err)
+	}
+
+	tw := tar.NewWriter(w)
+
+	if err := writeTarFile(tw, metaFile, 0o644, metaBytes); err != nil {
+		return err
+	}
+
+	if len(tlsFiles) > 0 {
+		if err := writeTarDir(tw, archiveTLSDir); err != nil {
+			return err
+		}
+	}
+
+	for _, endpointFiles := range tlsFiles {
+		if err := writeTarDir(tw, path.Join(archiveTLSDir, endpointFiles.endpoint)); err != nil {
+			return err
+		}
+
+		for _, name := range endpointFiles.files {
+			data, err := os.ReadFile(filepath.Join(tlsDir(contextName, metaRoot), endpointFiles.endpoint, name))
+			if err != nil {
+				return fmt.Errorf("read tls file: %w", err)
+			}
+
+			if err := writeTarFile(tw, path.Join(archiveTLSDir, endpointFiles.endpoint, name), 0o600, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return nil
+}
+
+// Import reads a context from a tar archive in the format of "docker context export",
+// and stores it with the given name under the given metadata root.
+// It returns [ErrContextExists] if a context with the same name already exists.
+//
+// Archives bigger than 10MiB, and entries escaping the archive, are rejected. Entries
+// other than regular files, and unknown files, are skipped, as in the docker CLI.
+func Import(contextName string, metaRoot string, r io.Reader) error {
+	_, err := find(contextName, metaRoot)
+	if err == nil {
+		return fmt.Errorf("context %q: %w", contextName, ErrContextExists)
+	}
+	if !errors.Is(err, ErrContextNotFound) {
+		return err
+	}
+
+	var meta *metadata
+	tlsData := map[string]map[string][]byte{}
+
+	// the whole archive is limited, so it can't fill the memory with many files
+	lr := &io.LimitedReader{R: r, N: maxImportFileSize}
+
+	tr := tar.NewReader(lr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return archiveReadError(lr, err)
+		}
+
+		if err := validateArchivePath(hdr.Name); err != nil {
+			return err
+		}
+
+		// only the regular files are imported, e.g. skipping directories and links
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := readArchiveFile(tr, hdr)
+		if err != nil {
+			return archiveReadError(lr, err)
+		}
+
+		switch {
+		case hdr.Name == metaFile:
+			meta = &metadata{}
+			if err := json.Unmarshal(data, meta); err != nil {
+				return fmt.Errorf("parse metadata: %w", err)
+			}
+		case strings.HasPrefix(hdr.Name, archiveTLSDir+"/"):
+			endpoint, name, ok := strings.Cut(strings.TrimPrefix(hdr.Name, archiveTLSDir+"/"), "/")
+			if !ok || strings.Contains(name, "/") {
+				return fmt.Errorf("invalid archive entry %q: archive format is invalid", hdr.Name)
+			}
+			if tlsData[endpoint] == nil {
+				tlsData[endpoint] = map[string][]byte{}
+			}
+			tlsData[endpoint][name] = data
+		}
+	}
+
+	if meta == nil {
+		return errors.New("invalid context: no metadata found")
+	}
+
+	for endpoint, files := range tlsData {
+		dir := filepath.Join(tlsDir(contextName, metaRoot), endpoint)
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("create tls dir: %w", err)
+		}
+
+		for name, data := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+				return fmt.Errorf("write tls file: %w", err)
+			}
+		}
+	}
+
+	// the metadata goes last, so the context is not visible until fully imported
+	meta.Name = contextName
+	return writeMetadata(filepath.Join(metaRoot, contextDir(contextName)), meta)
+}
+
+// validateArchivePath rejects archive entries which are absolute, or escape the archive.
+func validateArchivePath(name string) error {
+	clean := path.Clean(name)
+	if path.IsAbs(name) || clean == ".." || strings.HasPrefix(clean, "../") || strings.Contains(name, `\`) {
+		return fmt.Errorf("invalid archive entry %q: path traversal", name)
+	}
+	if clean != strings.TrimSuffix(name, "/") {
+		return fmt.Errorf("invalid archive entry %q: path is not clean", name)
+	}
+	return nil
+}
+
+// archiveReadError returns the error reading the archive, reporting archives
+// truncated by the size limit as too large.
+func archiveReadError(lr *io.LimitedReader, err error) error {
+	if lr.N <= 0 {
+		return errors.New("invalid archive: larger than 10MiB")
+	}
+	return err
+}
+
+// readArchiveFile reads the current file of the archive, rejecting oversized ones.
+func readArchiveFile(tr *tar.Reader, hdr *tar.Header) ([]byte, error) {
+	if hdr.Size > maxImportFileSize {
+		return nil, fmt.Errorf("invalid archive entry %q: file too large", hdr.Name)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(tr, maxImportFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read archive entry %q: %w", hdr.Name, err)
+	}
+	if len(data) > maxImportFileSize {
+		return nil, fmt.Errorf("invalid archive entry %q: file too large", hdr.Name)
+	}
+
+	return data, nil
+}
+
+// endpointTLSFiles holds the names of the TLS files of an endpoint
+type endpointTLSFiles struct {
+	endpoint string
+	files    []string
+}
+
+// listTLSFiles lists the TLS files of each endpoint in the given TLS directory,
+// sorted by endpoint and file name.
+func listTLSFiles(dir string) ([]endpointTLSFiles, error) {
+	endpoints, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var result []endpointTLSFiles
+	for _, ep := range endpoints {
+		if !ep.IsDir() {
+			continue
+		}
+
+		entries, err := os.ReadDir(filepath.Join(dir, ep.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		files := endpointTLSFiles{endpoint: ep.Name()}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				files.files = append(files.files, entry.Name())
+			}
+		}
+		if len(files.files) == 0 {
+			continue
+		}
+		sort.Strings(files.files)
+		result = append(result, files)
+	}
+
+	return result, nil
+}
+
+// writeTarFile writes a regular file into the archive
+func writeTarFile(tw *tar.Writer, name string, mode int64, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: mode,
+		Size: int64(len(data)),
+	}); err != nil {
+		return fmt.Errorf("write header %q: %w", name, err)
+	}
+
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// writeTarDir writes a directory into the archive
+func writeTarDir(tw *tar.Writer, name string) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0o700,
+		Typeflag: tar.TypeDir,
+	}); err != nil {
+		return fmt.Errorf("write header %q: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	t.Run("docker-cli-format", func(t *testing.T) {
+		metaRoot := filepath.Join(t.TempDir(), "meta")
+		require.NoError(t, Create(Context{
+			Name:        "remote",
+			Description: "remote host",
+			Endpoints: map[string]Endpoint{
+				"docker": {Host: "tcp://1.2.3.4:2376"},
+			},
+		}, metaRoot))
+
+		dir := filepath.Join(tlsDir("remote", metaRoot), "docker")
+		writeTLSFile(t, dir, keyFile, []byte("key"))
+		writeTLSFile(t, dir, caFile, []byte("ca"))
+		writeTLSFile(t, dir, certFile, []byte("cert"))
+
+		var buf bytes.Buffer
+		require.NoError(t, Export("remote", metaRoot, &buf))
+
+		require.Equal(t, []archiveEntry{
+			{name: "meta.json", typeflag: tar.TypeReg, mode: 0o644, data: `{"Name":"remote","Metadata":{"Description":"remote host"},"Endpoints":{"docker":{"Host":"tcp://1.2.3.4:2376","SkipTLSVerify":false}}}`},
+			{name: "tls", typeflag: tar.TypeDir, mode: 0o700},
+			{name: "tls/docker", typeflag: tar.TypeDir, mode: 0o700},
+			{name: "tls/docker/ca.pem", typeflag: tar.TypeReg, mode: 0o600, data: "ca"},
+			{name: "tls/docker/cert.pem", typeflag: tar.TypeReg, mode: 0o600, data: "cert"},
+			{name: "tls/docker/key.pem", typeflag: tar.TypeReg, mode: 0o600, data: "key"},
+		}, readArchive(t, &buf))
+	})
+
+	t.Run("no-tls", func(t *testing.T) {
+		metaRoot := filepath.Join(t.TempDir(), "meta")
+		require.NoError(t, Create(Context{
+			Name:      "remote",
+			Endpoints: map[string]Endpoint{"docker": {Host: "ssh://me@remote"}},
+		}, metaRoot))
+
+		// an endpoint directory without TLS files is not exported either
+		require.NoError(t, os.MkdirAll(filepath.Join(tlsDir("remote", metaRoot), "docker"), 0o700))
+
+		var buf bytes.Buffer
+		require.NoError(t, Export("remote", metaRoot, &buf))
+
+		require.Equal(t, []archiveEntry{
+			{name: "meta.json", typeflag: tar.TypeReg, mode: 0o644, data: `{"Name":"remote","Metadata":{},"Endpoints":{"docker":{"Host":"ssh://me@remote","SkipTLSVerify":false}}}`},
+		}, readArchive(t, &buf))
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		err := Export("remote", t.TempDir(), io.Discard)
+		require.ErrorIs(t, err, ErrContextNotFound)
+	})
+}
+
+func TestImport(t *testing.T) {
+	t.Run("round-trip", func(t *testing.T) {
+		srcRoot := filepath.Join(t.TempDir(), "meta")
+		require.NoError(t, Create(Context{
+			Name:        "remote",
+			Description: "remote host",
+			Fields:      map[string]any{"owner": "me"},
+			Endpoints: map[string]Endpoint{
+				"docker": {Host: "tcp://1.2.3.4:2376"},
+			},
+		}, srcRoot))
+		writeTLSFile(t, filepath.Join(tlsDir("remote", srcRoot), "docker"), caFile, []byte("ca"))
+
+		var buf bytes.Buffer
+		require.NoError(t, Export("remote", srcRoot, &buf))
+
+		dstRoot := filepath.Join(t.TempDir(), "meta")
+		require.NoError(t, Import("imported", dstRoot, &buf))
+
+		ctx, err := Inspect("imported", dstRoot)
+		require.NoError(t, err)
+		require.Equal(t, "imported", ctx.Name)
+		require.Equal(t, "remote host", ctx.Description)
+		require.Equal(t, map[string]any{"owner": "me"}, ctx.Fields)
+		require.Equal(t, "tcp://1.2.3.4:2376", ctx.Endpoints["docker"].Host)
+		require.Equal(t, filepath.Join(dstRoot, contextDir("imported")), ctx.Storage.MetadataPath)
+
+		data, err := LoadTLSData("imported", "docker", dstRoot)
+		require.NoError(t, err)
+		require.Equal(t, []byte("ca"), data.CA)
+	})
+
+	t.Run("skip-unsupported-entries", func(t *testing.T) {
+		metaRoot := filepath.Join(t.TempDir(), "meta")
+
+		err := Import("remote", metaRoot, archive(t,
+			tarEntry{name: metaFile, data: `{"Name":"remote","Endpoints":{"docker":{"Host":"tcp://1.2.3.4:2376"}}}`},
+			tarEntry{name: "README.md", data: "unknown file"},
+			tarEntry{name: "tls", typeflag: tar.TypeDir},
+			tarEntry{name: "tls/docker", typeflag: tar.TypeDir},
+			tarEntry{name: "tls/docker/ca.pem", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"},
+			tarEntry{name: "tls/docker/cert.pem", typeflag: tar.TypeFifo},
+		))
+		require.NoError(t, err)
+
+		ctx, err := Inspect("remote", metaRoot)
+		require.NoError(t, err)
+		require.Equal(t, "tcp://1.2.3.4:2376", ctx.Endpoints["docker"].Host)
+
+		_, err = os.Stat(tlsDir("remote", metaRoot))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("already-exists", func(t *testing.T) {
+		metaRoot := t.TempDir()
+		setupTestContext(t, metaRoot, "remote", metadata{Name: "remote"})
+
+		err := Import("remote", metaRoot, archive(t, tarEntry{name: metaFile, data: "{}"}))
+		require.ErrorIs(t, err, ErrContextExists)
+	})
+
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		wantErr string
+	}{
+		{name: "no-metadata", entries: []tarEntry{{name: "tls", typeflag: tar.TypeDir}}, wantErr: "no metadata found"},
+		{name: "invalid-metadata", entries: []tarEntry{{name: metaFile, data: "invalid json"}}, wantErr: "parse metadata"},
+		{name: "path-traversal", entries: []tarEntry{{name: "../meta.json", data: "{}"}}, wantErr: "path traversal"},
+		{name: "path-traversal/tls", entries: []tarEntry{{name: "tls/../../ca.pem", data: "ca"}}, wantErr: "path traversal"},
+		{name: "absolute-path", entries: []tarEntry{{name: "/meta.json", data: "{}"}}, wantErr: "path traversal"},
+		{name: "unclean-path", entries: []tarEntry{{name: "tls/docker/../ca.pem", data: "ca"}}, wantErr: "path is not clean"},
+		{name: "symlink", entries: []tarEntry{{name: metaFile, typeflag: tar.TypeSymlink, linkname: "/etc/passwd"}}, wantErr: "no metadata found"},
+		{name: "invalid-tls-path", entries: []tarEntry{{name: "tls/ca.pem", data: "ca"}}, wantErr: "archive format is invalid"},
+		{name: "nested-tls-path", entries: []tarEntry{{name: "tls/docker/nested/ca.pem", data: "ca"}}, wantErr: "archive format is invalid"},
+		{name: "too-large", entries: []tarEntry{{name: metaFile, data: string(make([]byte, maxImportFileSize+1))}}, wantErr: "file too large"},
+		{name: "too-large/archive", entries: []tarEntry{
+			{name: "tls/docker/ca.pem", data: string(make([]byte, 4<<20))},
+			{name: "tls/docker/cert.pem", data: string(make([]byte, 4<<20))},
+			{name: "tls/docker/key.pem", data: string(make([]byte, 4<<20))},
+			{name: metaFile, data: "{}"},
+		}, wantErr: "larger than 10MiB"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			metaRoot := filepath.Join(t.TempDir(), "meta")
+
+			err := Import("remote", metaRoot, archive(t, tc.entries...))
+			require.ErrorContains(t, err, tc.wantErr)
+
+			_, err = os.Stat(filepath.Join(metaRoot, contextDir("remote")))
+			require.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+// tarEntry is an entry of a test archive
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	data     string
+}
+
+// archive builds a tar archive with the given entries
+func archive(t *testing.T, entries ...tarEntry) io.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		typeflag := e.typeflag
+		if typeflag == 0 {
+			typeflag = tar.TypeReg
+		}
+
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     e.name,
+			Typeflag: typeflag,
+			Linkname: e.linkname,
+			Mode:     0o600,
+			Size:     int64(len(e.data)),
+		}))
+		_, err := tw.Write([]byte(e.data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	return &buf
+}
+
+// archiveEntry is an entry of an exported archive
+type archiveEntry struct {
+	name     string
+	typeflag byte
+	mode     int64
+	data     string
+}
+
+// readArchive returns the entries of the given tar archive.
+func readArchive(t *testing.T, r io.Reader) []archiveEntry {
+	t.Helper()
+
+	var entries []archiveEntry
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return entries
+		}
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		entries = append(entries, archiveEntry{name: hdr.Name, typeflag: hdr.Typeflag, mode: hdr.Mode, data: string(data)})
+	}
+}
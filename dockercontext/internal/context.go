@@ -1,14 +1,22 @@
 package internal
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
-const metaFile = "meta.json"
+const (
+	metaFile = "meta.json"
+
+	// tlsDirName is the name of the directory containing the TLS material, next to the metadata root
+	tlsDirName = "tls"
+)
 
 // dockerContext represents the metadata stored for a context
 type dockerContext struct {
@@ -16,17 +24,121 @@ type dockerContext struct {
 	Fields      map[string]any // renamed from AdditionalFields for brevity
 }
 
+// MarshalJSON inlines the additional fields next to the description, as the docker CLI does.
+func (dc dockerContext) MarshalJSON() ([]byte, error) {
+	s := make(map[string]any, len(dc.Fields)+1)
+	for k, v := range dc.Fields {
+		s[k] = v
+	}
+	if dc.Description != "" {
+		s["Description"] = dc.Description
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON reads the description, collecting any other key as an additional field.
+func (dc *dockerContext) UnmarshalJSON(payload []byte) error {
+	data, err := decodeFields(payload)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range data {
+		switch k {
+		case "Description":
+			description, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("invalid description type %T", v)
+			}
+			dc.Description = description
+		default:
+			if dc.Fields == nil {
+				dc.Fields = make(map[string]any)
+			}
+			dc.Fields[k] = v
+		}
+	}
+	return nil
+}
+
 // endpoint represents a Docker endpoint configuration
 type endpoint struct {
 	Host          string `json:",omitempty"`
 	SkipTLSVerify bool
+	Fields        map[string]any `json:"-"` // additional fields, e.g. DefaultNamespace for kubernetes
+
+	hasSkipTLSVerify bool // whether the SkipTLSVerify key was read
+}
+
+// MarshalJSON inlines the additional fields next to the host and TLS settings.
+// As in the docker CLI, SkipTLSVerify is written for the endpoints with a host,
+// but not added to custom endpoints which never had it.
+func (ep endpoint) MarshalJSON() ([]byte, error) {
+	s := make(map[string]any, len(ep.Fields)+2)
+	for k, v := range ep.Fields {
+		s[k] = v
+	}
+	if ep.Host != "" {
+		s["Host"] = ep.Host
+	}
+	if ep.Host != "" || ep.SkipTLSVerify || ep.hasSkipTLSVerify {
+		s["SkipTLSVerify"] = ep.SkipTLSVerify
+	}
+	return json.Marshal(s)
+}
+
+// UnmarshalJSON reads the host and TLS settings, collecting any other key as an additional field.
+func (ep *endpoint) UnmarshalJSON(payload []byte) error {
+	data, err := decodeFields(payload)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range data {
+		switch k {
+		case "Host":
+			host, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("invalid host type %T", v)
+			}
+			ep.Host = host
+		case "SkipTLSVerify":
+			skip, ok := v.(bool)
+			if !ok {
+				return fmt.Errorf("invalid skip TLS verify type %T", v)
+			}
+			ep.SkipTLSVerify = skip
+			ep.hasSkipTLSVerify = true
+		default:
+			if ep.Fields == nil {
+				ep.Fields = make(map[string]any)
+			}
+			ep.Fields[k] = v
+		}
+	}
+	return nil
+}
+
+// decodeFields decodes a JSON object, keeping its numbers as [json.Number],
+// so the additional fields are written back unchanged, e.g. integers above 2^53.
+func decodeFields(payload []byte) (map[string]any, error) {
+	var data map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 // metadata represents a complete context configuration
 type metadata struct {
 	Name      string               `json:",omitempty"`
-	Context   *dockerContext       `json:"metadata,omitempty"`
-	Endpoints map[string]*endpoint `json:"endpoints,omitempty"`
+	Context   *dockerContext       `json:"Metadata,omitempty"`
+	Endpoints map[string]*endpoint `json:"Endpoints,omitempty"`
+
+	dir string // directory the metadata was loaded from
 }
 
 // store manages Docker context metadata files
@@ -34,25 +146,168 @@ type store struct {
 	root string
 }
 
-// ExtractDockerHost extracts the Docker host from the given Docker context
-func ExtractDockerHost(contextName string, metaRoot string) (string, error) {
+// Context is the typed view of the metadata of a Docker context.
+type Context struct {
+	// Name is the name of the context.
+	Name string
+
+	// Description is the description of the context.
+	Description string
+
+	// Fields holds the additional metadata fields of the context,
+	// set by tools other than the docker CLI. Their numbers are read as [json.Number].
+	Fields map[string]any
+
+	// Endpoints holds the endpoints of the context, by type, e.g. "docker".
+	Endpoints map[string]Endpoint
+
+	// Storage holds the paths where the context is stored.
+	Storage Storage
+}
+
+// Storage holds the paths where a Docker context is stored.
+type Storage struct {
+	// MetadataPath is the directory holding the meta.json file of the context.
+	MetadataPath string
+
+	// TLSPath is the directory holding the TLS material of the context.
+	TLSPath string
+}
+
+// Endpoint is the typed view of an endpoint of a Docker context.
+type Endpoint struct {
+	// Host is the address of the endpoint, e.g. "unix:///var/run/docker.sock".
+	Host string
+
+	// SkipTLSVerify disables the verification of the server certificate.
+	SkipTLSVerify bool
+
+	// Fields holds the additional fields of the endpoint, e.g. "DefaultNamespace"
+	// for a kubernetes endpoint. Their numbers are read as [json.Number].
+	Fields map[string]any
+}
+
+// List returns all the contexts stored under the given metadata root,
+// sorted by name. The TLS material is expected in the "tls" directory
+// next to the metadata root, as the docker CLI does.
+func List(metaRoot string) ([]Context, error) {
+	s := &store{root: metaRoot}
+
+	metas, err := s.list()
+	if err != nil {
+		return nil, fmt.Errorf("list contexts: %w", err)
+	}
+
+	contexts := make([]Context, 0, len(metas))
+	for _, meta := range metas {
+		contexts = append(contexts, meta.toContext(metaRoot))
+	}
+
+	sort.Slice(contexts, func(i, j int) bool {
+		return contexts[i].Name < contexts[j].Name
+	})
+
+	return contexts, nil
+}
+
+// Inspect returns the context with the given name, stored under the given metadata root.
+// It returns [ErrContextNotFound] if there is no such context.
+func Inspect(contextName string, metaRoot string) (Context, error) {
+	meta, err := find(contextName, metaRoot)
+	if err != nil {
+		return Context{}, err
+	}
+
+	return meta.toContext(metaRoot), nil
+}
+
+// find returns the metadata of the context with the given name.
+// It returns [ErrContextNotFound] if there is no such context.
+//
+// It goes straight to the directory named after the digest of the context name,
+// where the docker CLI stores it, and only falls back to scanning the whole
+// metadata root for legacy or nested layouts.
+func find(contextName string, metaRoot string) (*metadata, error) {
 	s := &store{root: metaRoot}
 
+	meta, err := s.load(filepath.Join(metaRoot, contextDir(contextName)))
+	switch {
+	case err == nil && meta.Name == contextName:
+		return meta, nil
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("load context %s: %w", contextName, err)
+	}
+
 	contexts, err := s.list()
 	if err != nil {
-		return "", fmt.Errorf("list contexts: %w", err)
+		return nil, fmt.Errorf("list contexts: %w", err)
 	}
 
 	for _, ctx := range contexts {
 		if ctx.Name == contextName {
-			ep, ok := ctx.Endpoints["docker"]
-			if !ok || ep == nil || ep.Host == "" { // Check all conditions that should trigger the error
-				return "", ErrDockerHostNotSet
-			}
-			return ep.Host, nil
+			return ctx, nil
 		}
 	}
-	return "", ErrDockerHostNotSet
+
+	return nil, fmt.Errorf("context %q: %w", contextName, ErrContextNotFound)
+}
+
+// tlsDir returns the directory holding the TLS material of the given context,
+// which is named after the digest of the context name, as the docker CLI does.
+func tlsDir(contextName string, metaRoot string) string {
+	return filepath.Join(filepath.Dir(metaRoot), tlsDirName, contextDir(contextName))
+}
+
+// contextDir returns the name of the directory of the given context, inside
+// the metadata and TLS roots: the hex encoded SHA-256 digest of its name.
+func contextDir(contextName string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(contextName)))
+}
+
+// toContext converts the metadata into its typed view.
+func (m *metadata) toContext(metaRoot string) Context {
+	ctx := Context{
+		Name:      m.Name,
+		Endpoints: make(map[string]Endpoint, len(m.Endpoints)),
+		Storage: Storage{
+			MetadataPath: m.dir,
+			TLSPath:      tlsDir(m.Name, metaRoot),
+		},
+	}
+
+	if m.Context != nil {
+		ctx.Description = m.Context.Description
+		ctx.Fields = m.Context.Fields
+	}
+
+	for name, ep := range m.Endpoints {
+		if ep == nil {
+			continue
+		}
+		ctx.Endpoints[name] = Endpoint{
+			Host:          ep.Host,
+			SkipTLSVerify: ep.SkipTLSVerify,
+			Fields:        ep.Fields,
+		}
+	}
+
+	return ctx
+}
+
+// ExtractDockerHost extracts the Docker host from the given Docker context.
+// It returns [ErrContextNotFound] if there is no such context, and
+// [ErrDockerHostNotSet] if the context has no Docker host.
+func ExtractDockerHost(contextName string, metaRoot string) (string, error) {
+	ctx, err := find(contextName, metaRoot)
+	if err != nil {
+		return "", err
+	}
+
+	ep, ok := ctx.Endpoints["docker"]
+	if !ok || ep == nil || ep.Host == "" { // Check all conditions that should trigger the error
+		return "", ErrDockerHostNotSet
+	}
+	return ep.Host, nil
 }
 
 func (s *store) list() ([]*metadata, error) {
@@ -88,6 +343,7 @@ func (s *store) load(dir string) (*metadata, error) {
 	if err := json.Unmarshal(data, &meta); err != nil {
 		return nil, fmt.Errorf("parse metadata: %w", err)
 	}
+	meta.dir = dir
 	return &meta, nil
 }
 
@@ -112,3 +368,28 @@ func hasMetaFile(dir string) bool {
 	info, err := os.Stat(filepath.Join(dir, metaFile))
 	return err == nil && !info.IsDir()
 }
+
+// EndpointJSON returns the JSON encoding of an endpoint, as stored in meta.json,
+// with its additional fields inlined.
+func EndpointJSON(ep Endpoint) ([]byte, error) {
+	return json.Marshal(endpoint{
+		Host:          ep.Host,
+		SkipTLSVerify: ep.SkipTLSVerify,
+		Fields:        ep.Fields,
+	})
+}
+
+// EndpointFromJSON decodes an endpoint from its JSON encoding, as stored in meta.json,
+// collecting the keys other than the host and TLS settings as additional fields.
+func EndpointFromJSON(data []byte) (Endpoint, error) {
+	var ep endpoint
+	if err := json.Unmarshal(data, &ep); err != nil {
+		return Endpoint{}, err
+	}
+
+	return Endpoint{
+		Host:          ep.Host,
+		SkipTLSVerify: ep.SkipTLSVerify,
+		Fields:        ep.Fields,
+	}, nil
+}
@@ -36,7 +36,7 @@ func TestExtractDockerHost(t *testing.T) {
 			Endpoints: map[string]*endpoint{
 				"docker": {Host: "tcp://1.2.3.4:2375"},
 			},
-		}, ErrDockerHostNotSet)
+		}, ErrContextNotFound)
 	})
 
 	t.Run("nested-context-found", func(t *testing.T) {
@@ -50,6 +50,66 @@ func TestExtractDockerHost(t *testing.T) {
 	})
 }
 
+func TestFind(t *testing.T) {
+	t.Run("digest-dir/skips-scan", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		setupTestContext(t, tmpDir, contextDir("remote"), metadata{
+			Name: "remote",
+			Endpoints: map[string]*endpoint{
+				"docker": {Host: "tcp://1.2.3.4:2375"},
+			},
+		})
+
+		// a corrupted context would make the scan fail
+		invalidDir := filepath.Join(tmpDir, "invalid")
+		require.NoError(t, os.MkdirAll(invalidDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(invalidDir, metaFile), []byte("invalid json"), 0o644))
+
+		meta, err := find("remote", tmpDir)
+		require.NoError(t, err)
+		require.Equal(t, "tcp://1.2.3.4:2375", meta.Endpoints["docker"].Host)
+		require.Equal(t, filepath.Join(tmpDir, contextDir("remote")), meta.dir)
+	})
+
+	t.Run("digest-dir/invalid", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		dir := filepath.Join(tmpDir, contextDir("remote"))
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, metaFile), []byte("invalid json"), 0o644))
+
+		_, err := find("remote", tmpDir)
+		require.ErrorContains(t, err, "parse metadata")
+	})
+
+	t.Run("digest-dir/name-mismatch", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		setupTestContext(t, tmpDir, contextDir("remote"), metadata{Name: "other"})
+		setupTestContext(t, tmpDir, "legacy", metadata{Name: "remote"})
+
+		meta, err := find("remote", tmpDir)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(tmpDir, "legacy"), meta.dir)
+	})
+
+	t.Run("fallback-scan", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		setupTestContext(t, tmpDir, "parent/legacy", metadata{Name: "remote"})
+
+		meta, err := find("remote", tmpDir)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(tmpDir, "parent", "legacy"), meta.dir)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		_, err := find("remote", t.TempDir())
+		require.ErrorIs(t, err, ErrContextNotFound)
+	})
+}
+
 func TestStore_load(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -388,6 +448,200 @@ func TestStore_list(t *testing.T) {
 	})
 }
 
+func TestList(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		setupTestContext(t, tmpDir, "context2", metadata{
+			Name: "context2",
+			Endpoints: map[string]*endpoint{
+				"docker": {Host: "unix:///var/run/docker.sock", SkipTLSVerify: true},
+			},
+		})
+		setupTestContext(t, tmpDir, "nested/context1", metadata{
+			Name: "context1",
+			Context: &dockerContext{
+				Description: "context 1",
+				Fields:      map[string]any{"owner": "me"},
+			},
+			Endpoints: map[string]*endpoint{
+				"docker": {Host: "tcp://1.2.3.4:2375"},
+			},
+		})
+
+		contexts, err := List(tmpDir)
+		require.NoError(t, err)
+		require.Equal(t, []Context{
+			{
+				Name:        "context1",
+				Description: "context 1",
+				Fields:      map[string]any{"owner": "me"},
+				Endpoints: map[string]Endpoint{
+					"docker": {Host: "tcp://1.2.3.4:2375"},
+				},
+				Storage: Storage{
+					MetadataPath: filepath.Join(tmpDir, "nested", "context1"),
+					TLSPath:      tlsDir("context1", tmpDir),
+				},
+			},
+			{
+				Name: "context2",
+				Endpoints: map[string]Endpoint{
+					"docker": {Host: "unix:///var/run/docker.sock", SkipTLSVerify: true},
+				},
+				Storage: Storage{
+					MetadataPath: filepath.Join(tmpDir, "context2"),
+					TLSPath:      tlsDir("context2", tmpDir),
+				},
+			},
+		}, contexts)
+	})
+
+	t.Run("root-does-not-exist", func(t *testing.T) {
+		contexts, err := List(filepath.Join(t.TempDir(), "does-not-exist"))
+		require.NoError(t, err)
+		require.Empty(t, contexts)
+	})
+}
+
+func TestInspect(t *testing.T) {
+	t.Run("all-endpoints", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		metaRoot := filepath.Join(tmpDir, "meta")
+
+		contextDir := filepath.Join(metaRoot, "remote")
+		require.NoError(t, os.MkdirAll(contextDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(contextDir, metaFile), []byte(`{
+			"Name": "remote",
+			"Metadata": {"Description": "remote host", "owner": "me"},
+			"Endpoints": {
+				"docker": {"Host": "tcp://1.2.3.4:2376", "SkipTLSVerify": true},
+				"kubernetes": {"Host": "https://1.2.3.4:6443", "SkipTLSVerify": false, "DefaultNamespace": "dev"},
+				"custom": {"Token": "abc"}
+			}
+		}`), 0o644))
+
+		ctx, err := Inspect("remote", metaRoot)
+		require.NoError(t, err)
+		require.Equal(t, Context{
+			Name:        "remote",
+			Description: "remote host",
+			Fields:      map[string]any{"owner": "me"},
+			Endpoints: map[string]Endpoint{
+				"docker":     {Host: "tcp://1.2.3.4:2376", SkipTLSVerify: true},
+				"kubernetes": {Host: "https://1.2.3.4:6443", Fields: map[string]any{"DefaultNamespace": "dev"}},
+				"custom":     {Fields: map[string]any{"Token": "abc"}},
+			},
+			Storage: Storage{
+				MetadataPath: contextDir,
+				TLSPath:      filepath.Join(tmpDir, "tls", "b71199ebd070b36beab7317920c2c2f1d777df8d05e5527d8458fda57cb17a7a"),
+			},
+		}, ctx)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		setupTestContext(t, tmpDir, "other", metadata{Name: "other"})
+
+		_, err := Inspect("missing", tmpDir)
+		require.ErrorIs(t, err, ErrContextNotFound)
+	})
+}
+
+func TestEndpoint_JSON(t *testing.T) {
+	t.Run("unmarshal-additional-fields", func(t *testing.T) {
+		var ep endpoint
+		err := json.Unmarshal([]byte(`{"Host":"tcp://1.2.3.4:2375","SkipTLSVerify":true,"DefaultNamespace":"dev"}`), &ep)
+		require.NoError(t, err)
+		require.Equal(t, endpoint{
+			Host:          "tcp://1.2.3.4:2375",
+			SkipTLSVerify: true,
+			Fields:        map[string]any{"DefaultNamespace": "dev"},
+
+			hasSkipTLSVerify: true,
+		}, ep)
+	})
+
+	t.Run("unmarshal-invalid-host", func(t *testing.T) {
+		var ep endpoint
+		err := json.Unmarshal([]byte(`{"Host":1}`), &ep)
+		require.ErrorContains(t, err, "invalid host type")
+	})
+
+	t.Run("unmarshal-invalid-skip-tls-verify", func(t *testing.T) {
+		var ep endpoint
+		err := json.Unmarshal([]byte(`{"SkipTLSVerify":"yes"}`), &ep)
+		require.ErrorContains(t, err, "invalid skip TLS verify type")
+	})
+
+	t.Run("marshal-inlines-fields", func(t *testing.T) {
+		data, err := json.Marshal(endpoint{
+			Host:   "tcp://1.2.3.4:2375",
+			Fields: map[string]any{"DefaultNamespace": "dev"},
+		})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"Host":"tcp://1.2.3.4:2375","SkipTLSVerify":false,"DefaultNamespace":"dev"}`, string(data))
+	})
+
+	t.Run("round-trip/custom", func(t *testing.T) {
+		payload := `{"Port":9007199254740993,"Token":"abc"}`
+
+		var ep endpoint
+		require.NoError(t, json.Unmarshal([]byte(payload), &ep))
+
+		data, err := json.Marshal(ep)
+		require.NoError(t, err)
+		require.Equal(t, payload, string(data))
+	})
+
+	t.Run("round-trip/skip-tls-verify", func(t *testing.T) {
+		payload := `{"SkipTLSVerify":false,"Token":"abc"}`
+
+		var ep endpoint
+		require.NoError(t, json.Unmarshal([]byte(payload), &ep))
+
+		data, err := json.Marshal(ep)
+		require.NoError(t, err)
+		require.Equal(t, payload, string(data))
+	})
+}
+
+func TestDockerContext_JSON(t *testing.T) {
+	t.Run("unmarshal-additional-fields", func(t *testing.T) {
+		var dc dockerContext
+		err := json.Unmarshal([]byte(`{"Description":"desc","owner":"me","count":1}`), &dc)
+		require.NoError(t, err)
+		require.Equal(t, "desc", dc.Description)
+		require.Equal(t, map[string]any{"owner": "me", "count": json.Number("1")}, dc.Fields)
+	})
+
+	t.Run("round-trip/large-integer", func(t *testing.T) {
+		payload := `{"Description":"desc","id":9007199254740993,"nested":{"id":18446744073709551615}}`
+
+		var dc dockerContext
+		require.NoError(t, json.Unmarshal([]byte(payload), &dc))
+
+		data, err := json.Marshal(dc)
+		require.NoError(t, err)
+		require.Equal(t, payload, string(data))
+	})
+
+	t.Run("unmarshal-invalid-description", func(t *testing.T) {
+		var dc dockerContext
+		err := json.Unmarshal([]byte(`{"Description":1}`), &dc)
+		require.ErrorContains(t, err, "invalid description type")
+	})
+
+	t.Run("marshal-inlines-fields", func(t *testing.T) {
+		data, err := json.Marshal(dockerContext{
+			Description: "desc",
+			Fields:      map[string]any{"owner": "me"},
+		})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"Description":"desc","owner":"me"}`, string(data))
+	})
+}
+
 // requireDockerHost creates a context and verifies host extraction succeeds
 func requireDockerHost(t *testing.T, contextName string, meta metadata) string {
 	t.Helper()
@@ -2,4 +2,11 @@ package internal
 
 import "errors"
 
+// ErrDockerHostNotSet is the error returned when the Docker host is not set in the Docker context
 var ErrDockerHostNotSet = errors.New("docker host not set in Docker context")
+
+// ErrContextNotFound is the error returned when the Docker context does not exist
+var ErrContextNotFound = errors.New("docker context not found")
+
+// ErrContextExists is the error returned when creating a Docker context that already exists
+var ErrContextExists = errors.New("docker context already exists")
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Names of the TLS files stored for each endpoint, as the docker CLI does.
+const (
+	caFile   = "ca.pem"
+	certFile = "cert.pem"
+	keyFile  = "key.pem"
+)
+
+// TLSData holds the PEM encoded TLS material of an endpoint.
+type TLSData struct {
+	CA   []byte
+	Cert []byte
+	Key  []byte
+}
+
+// LoadTLSData loads the TLS material of the given endpoint of a context, stored in
+// the "tls/<sha256(name)>/<endpoint>" directory next to the metadata root.
+// It returns nil if the endpoint has no TLS material.
+func LoadTLSData(contextName string, endpointName string, metaRoot string) (*TLSData, error) {
+	return LoadTLSDataFromDir(filepath.Join(tlsDir(contextName, metaRoot), endpointName))
+}
+
+// LoadTLSDataFromDir loads the ca.pem, cert.pem and key.pem files from the given directory.
+// It returns nil if none of them exist.
+func LoadTLSDataFromDir(dir string) (*TLSData, error) {
+	var data TLSData
+	var found bool
+	for name, dst := range map[string]*[]byte{caFile: &data.CA, certFile: &data.Cert, keyFile: &data.Key} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+		*dst = b
+		found = true
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	if (data.Cert == nil) != (data.Key == nil) {
+		return nil, fmt.Errorf("incomplete TLS material in %s: both %s and %s are required", dir, certFile, keyFile)
+	}
+
+	return &data, nil
+}
+
+// TLSConfig builds a TLS configuration from the TLS material and the
+// skip TLS verify setting of an endpoint. It returns nil if there is no
+// TLS material and the verification is not skipped, meaning the endpoint
+// doesn't use TLS.
+func TLSConfig(data *TLSData, skipTLSVerify bool) (*tls.Config, error) {
+	if data == nil && !skipTLSVerify {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: skipTLSVerify, //nolint:gosec // Explicitly set in the context endpoint.
+	}
+
+	if data == nil {
+		return cfg, nil
+	}
+
+	if len(data.CA) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data.CA) {
+			return nil, errors.New("invalid CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if len(data.Cert) > 0 {
+		cert, err := tls.X509KeyPair(data.Cert, data.Key)
+		if err != nil {
+			return nil, fmt.Errorf("load key pair: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
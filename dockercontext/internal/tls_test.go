@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTLSData(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		metaRoot := filepath.Join(t.TempDir(), "meta")
+		certPEM, keyPEM := readCertificate(t)
+
+		dir := filepath.Join(tlsDir("remote", metaRoot), "docker")
+		writeTLSFile(t, dir, caFile, certPEM)
+		writeTLSFile(t, dir, certFile, certPEM)
+		writeTLSFile(t, dir, keyFile, keyPEM)
+
+		data, err := LoadTLSData("remote", "docker", metaRoot)
+		require.NoError(t, err)
+		require.Equal(t, &TLSData{CA: certPEM, Cert: certPEM, Key: keyPEM}, data)
+	})
+
+	t.Run("no-tls-material", func(t *testing.T) {
+		data, err := LoadTLSData("remote", "docker", filepath.Join(t.TempDir(), "meta"))
+		require.NoError(t, err)
+		require.Nil(t, data)
+	})
+
+	t.Run("missing-key", func(t *testing.T) {
+		metaRoot := filepath.Join(t.TempDir(), "meta")
+		certPEM, _ := readCertificate(t)
+
+		writeTLSFile(t, filepath.Join(tlsDir("remote", metaRoot), "docker"), certFile, certPEM)
+
+		_, err := LoadTLSData("remote", "docker", metaRoot)
+		require.ErrorContains(t, err, "incomplete TLS material")
+	})
+}
+
+func TestTLSConfig(t *testing.T) {
+	certPEM, keyPEM := readCertificate(t)
+
+	t.Run("no-tls", func(t *testing.T) {
+		cfg, err := TLSConfig(nil, false)
+		require.NoError(t, err)
+		require.Nil(t, cfg)
+	})
+
+	t.Run("skip-tls-verify", func(t *testing.T) {
+		cfg, err := TLSConfig(nil, true)
+		require.NoError(t, err)
+		require.True(t, cfg.InsecureSkipVerify)
+		require.Nil(t, cfg.RootCAs)
+		require.Empty(t, cfg.Certificates)
+	})
+
+	t.Run("ca-and-key-pair", func(t *testing.T) {
+		cfg, err := TLSConfig(&TLSData{CA: certPEM, Cert: certPEM, Key: keyPEM}, false)
+		require.NoError(t, err)
+		require.False(t, cfg.InsecureSkipVerify)
+		require.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+		require.NotNil(t, cfg.RootCAs)
+		require.Len(t, cfg.Certificates, 1)
+	})
+
+	t.Run("invalid-ca", func(t *testing.T) {
+		_, err := TLSConfig(&TLSData{CA: []byte("invalid")}, false)
+		require.ErrorContains(t, err, "invalid CA certificate")
+	})
+
+	t.Run("invalid-key-pair", func(t *testing.T) {
+		_, err := TLSConfig(&TLSData{Cert: certPEM, Key: []byte("invalid")}, false)
+		require.ErrorContains(t, err, "load key pair")
+	})
+}
+
+// writeTLSFile writes a TLS file into the given directory, creating it if needed
+func writeTLSFile(t *testing.T, dir, name string, data []byte) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+}
+
+// readCertificate returns the self-signed certificate of the testdata directory and its key,
+// PEM encoded. The certificate is also its own CA.
+func readCertificate(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	certPEM, err := os.ReadFile(filepath.Join("testdata", "tls", certFile))
+	require.NoError(t, err)
+
+	keyPEM, err := os.ReadFile(filepath.Join("testdata", "tls", keyFile))
+	require.NoError(t, err)
+
+	return certPEM, keyPEM
+}
@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// restrictedNamePattern is the pattern used by the docker CLI to validate context names
+const restrictedNamePattern = "^[a-zA-Z0-9][a-zA-Z0-9_.+-]+$"
+
+// restrictedNameRegEx is the compiled form of restrictedNamePattern
+//
+//nolint:gochecknoglobals // Compiled once, as the docker CLI does.
+var restrictedNameRegEx = regexp.MustCompile(restrictedNamePattern)
+
+// ValidateName checks the name of a context against the rules of the docker CLI.
+// The name of the default context is reserved, so it's not valid.
+func ValidateName(name string, defaultName string) error {
+	if name == "" {
+		return errors.New("context name cannot be empty")
+	}
+	if name == defaultName {
+		return fmt.Errorf("%q is a reserved context name", name)
+	}
+	if !restrictedNameRegEx.MatchString(name) {
+		return fmt.Errorf("context name %q is invalid, names are validated against regexp %q", name, restrictedNamePattern)
+	}
+	return nil
+}
+
+// Create stores a new context under the given metadata root, in the directory
+// named after the digest of its name, as the docker CLI does.
+// It returns [ErrContextExists] if a context with the same name already exists.
+func Create(ctx Context, metaRoot string) error {
+	_, err := find(ctx.Name, metaRoot)
+	if err == nil {
+		return fmt.Errorf("context %q: %w", ctx.Name, ErrContextExists)
+	}
+	if !errors.Is(err, ErrContextNotFound) {
+		return err
+	}
+
+	return writeMetadata(filepath.Join(metaRoot, contextDir(ctx.Name)), fromContext(ctx))
+}
+
+// Update replaces the metadata of an existing context under the given metadata root.
+// It returns [ErrContextNotFound] if there is no such context.
+func Update(ctx Context, metaRoot string) error {
+	meta, err := find(ctx.Name, metaRoot)
+	if err != nil {
+		return err
+	}
+
+	return writeMetadata(meta.dir, fromContext(ctx))
+}
+
+// Remove deletes the metadata and the TLS material of the context with the given name.
+// It returns [ErrContextNotFound] if there is no such context.
+func Remove(contextName string, metaRoot string) error {
+	meta, err := find(contextName, metaRoot)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(meta.dir); err != nil {
+		return fmt.Errorf("remove metadata: %w", err)
+	}
+
+	if err := os.RemoveAll(tlsDir(contextName, metaRoot)); err != nil {
+		return fmt.Errorf("remove tls: %w", err)
+	}
+
+	return nil
+}
+
+// fromContext converts the typed view of a context into its metadata.
+func fromContext(ctx Context) *metadata {
+	meta := &metadata{
+		Name: ctx.Name,
+		Context: &dockerContext{
+			Description: ctx.Description,
+			Fields:      ctx.Fields,
+		},
+		Endpoints: make(map[string]*endpoint, len(ctx.Endpoints)),
+	}
+
+	for name, ep := range ctx.Endpoints {
+		meta.Endpoints[name] = &endpoint{
+			Host:          ep.Host,
+			SkipTLSVerify: ep.SkipTLSVerify,
+			Fields:        ep.Fields,
+		}
+	}
+
+	return meta
+}
+
+// writeMetadata writes the metadata into the meta.json file of the given directory,
+// atomically replacing any existing one.
+func writeMetadata(dir string, meta *metadata) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("encode metadata: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create context dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, metaFile+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp metadata: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp metadata: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp metadata: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("chmod temp metadata: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, metaFile)); err != nil {
+		return fmt.Errorf("rename temp metadata: %w", err)
+	}
+
+	return nil
+}
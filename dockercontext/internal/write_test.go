@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"remote", "my-context", "ctx_1.2+dev", "0ctx"} {
+		t.Run("valid/"+name, func(t *testing.T) {
+			require.NoError(t, ValidateName(name, "default"))
+		})
+	}
+
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{name: "", wantErr: "context name cannot be empty"},
+		{name: "default", wantErr: `"default" is a reserved context name`},
+		{name: "a", wantErr: "is invalid"},
+		{name: "-ctx", wantErr: "is invalid"},
+		{name: "nested/ctx", wantErr: "is invalid"},
+		{name: "../ctx", wantErr: "is invalid"},
+	}
+
+	for _, tc := range tests {
+		t.Run("invalid/"+tc.name, func(t *testing.T) {
+			require.ErrorContains(t, ValidateName(tc.name, "default"), tc.wantErr)
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		metaRoot := filepath.Join(t.TempDir(), "meta")
+
+		ctx := Context{
+			Name:        "remote",
+			Description: "remote host",
+			Fields:      map[string]any{"owner": "me"},
+			Endpoints: map[string]Endpoint{
+				"docker": {Host: "tcp://1.2.3.4:2376", SkipTLSVerify: true},
+			},
+		}
+		require.NoError(t, Create(ctx, metaRoot))
+
+		data, err := os.ReadFile(filepath.Join(metaRoot, contextDir("remote"), metaFile))
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"Name": "remote",
+			"Metadata": {"Description": "remote host", "owner": "me"},
+			"Endpoints": {"docker": {"Host": "tcp://1.2.3.4:2376", "SkipTLSVerify": true}}
+		}`, string(data))
+
+		got, err := Inspect("remote", metaRoot)
+		require.NoError(t, err)
+		require.Equal(t, ctx.Endpoints, got.Endpoints)
+		require.Equal(t, ctx.Fields, got.Fields)
+	})
+
+	t.Run("already-exists", func(t *testing.T) {
+		metaRoot := t.TempDir()
+		setupTestContext(t, metaRoot, "legacy", metadata{Name: "remote"})
+
+		err := Create(Context{Name: "remote"}, metaRoot)
+		require.ErrorIs(t, err, ErrContextExists)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		metaRoot := t.TempDir()
+		setupTestContext(t, metaRoot, "legacy", metadata{
+			Name: "remote",
+			Endpoints: map[string]*endpoint{
+				"docker": {Host: "tcp://1.2.3.4:2375"},
+			},
+		})
+
+		ctx, err := Inspect("remote", metaRoot)
+		require.NoError(t, err)
+
+		ctx.Description = "updated"
+		ctx.Endpoints["docker"] = Endpoint{Host: "tcp://5.6.7.8:2375"}
+		require.NoError(t, Update(ctx, metaRoot))
+
+		got, err := Inspect("remote", metaRoot)
+		require.NoError(t, err)
+		require.Equal(t, "updated", got.Description)
+		require.Equal(t, "tcp://5.6.7.8:2375", got.Endpoints["docker"].Host)
+		require.Equal(t, filepath.Join(metaRoot, "legacy"), got.Storage.MetadataPath) // updated in place
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		err := Update(Context{Name: "remote"}, t.TempDir())
+		require.ErrorIs(t, err, ErrContextNotFound)
+	})
+}
+
+func TestRemove(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		metaRoot := filepath.Join(t.TempDir(), "meta")
+		require.NoError(t, Create(Context{Name: "remote"}, metaRoot))
+
+		tlsPath := tlsDir("remote", metaRoot)
+		require.NoError(t, os.MkdirAll(filepath.Join(tlsPath, "docker"), 0o700))
+
+		require.NoError(t, Remove("remote", metaRoot))
+
+		_, err := Inspect("remote", metaRoot)
+		require.ErrorIs(t, err, ErrContextNotFound)
+
+		_, err = os.Stat(filepath.Join(metaRoot, contextDir("remote")))
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		_, err = os.Stat(tlsPath)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		err := Remove("remote", t.TempDir())
+		require.ErrorIs(t, err, ErrContextNotFound)
+	})
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package dockercontext
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// dialPipe fails, as named pipes are only supported on Windows.
+func dialPipe(_ context.Context, addr string, _ time.Duration) (net.Conn, error) {
+	return nil, errors.New("npipe protocol is only supported on Windows: cannot dial " + addr)
+}
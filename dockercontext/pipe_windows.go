@@ -0,0 +1,199 @@
+//go:build windows
+
+package dockercontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// errPipeBusy is the ERROR_PIPE_BUSY error, returned when all the instances of a named pipe are busy.
+const errPipeBusy = syscall.Errno(231)
+
+// The kernel32 procedures needed for overlapped I/O, which are missing from the syscall package.
+// They are loaded here rather than using golang.org/x/sys/windows, as this module only depends
+// on the standard library, and os.NewFile can't wrap an overlapped handle before Go 1.25.
+//
+//nolint:gochecknoglobals // Lazily loaded procedures, like the ones of the syscall package.
+var (
+	modkernel32             = syscall.NewLazyDLL("kernel32.dll")
+	procCreateEventW        = modkernel32.NewProc("CreateEventW")
+	procGetOverlappedResult = modkernel32.NewProc("GetOverlappedResult")
+)
+
+// pipeAddr is the address of a named pipe.
+type pipeAddr string
+
+// Network returns the name of the network.
+func (a pipeAddr) Network() string { return "pipe" }
+
+// String returns the path of the named pipe.
+func (a pipeAddr) String() string { return string(a) }
+
+// pipeConn is a connection to a named pipe, opened for overlapped I/O. A synchronous
+// handle would serialize the reads and writes, so a pending read, as the HTTP transport
+// keeps while writing a request, would block the writes forever.
+type pipeConn struct {
+	handle syscall.Handle
+	addr   pipeAddr
+
+	// mu is held to start an I/O operation and to mark the connection closed, so
+	// every operation is either started before the cancellation, or not at all
+	mu     sync.Mutex
+	closed atomic.Bool
+
+	// pending counts the started I/O operations, so the handle is closed once they return
+	pending sync.WaitGroup
+}
+
+// dialPipe opens the named pipe at the given address, e.g. "//./pipe/docker_engine",
+// retrying while all its instances are busy, until the timeout expires.
+func dialPipe(ctx context.Context, addr string, timeout time.Duration) (net.Conn, error) {
+	path, err := syscall.UTF16PtrFromString(filepath.FromSlash(addr))
+	if err != nil {
+		return nil, fmt.Errorf("open named pipe %s: %w", addr, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		h, err := syscall.CreateFile(path, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil,
+			syscall.OPEN_EXISTING, syscall.FILE_FLAG_OVERLAPPED, 0)
+		if err == nil {
+			return &pipeConn{handle: h, addr: pipeAddr(addr)}, nil
+		}
+
+		if !errors.Is(err, errPipeBusy) || time.Now().After(deadline) {
+			return nil, fmt.Errorf("open named pipe %s: %w", addr, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// Read reads from the named pipe, returning [io.EOF] once the server closes it.
+func (c *pipeConn) Read(p []byte) (int, error) {
+	n, err := c.do(p, syscall.ReadFile)
+	if errors.Is(err, syscall.ERROR_BROKEN_PIPE) {
+		return n, io.EOF
+	}
+	return n, err
+}
+
+// Write writes to the named pipe.
+func (c *pipeConn) Write(p []byte) (int, error) {
+	var written int
+	for written < len(p) {
+		n, err := c.do(p[written:], syscall.WriteFile)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// do runs an overlapped I/O operation, waiting for its completion.
+func (c *pipeConn) do(p []byte, op func(syscall.Handle, []byte, *uint32, *syscall.Overlapped) error) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	event, err := createEvent()
+	if err != nil {
+		return 0, err
+	}
+	defer syscall.CloseHandle(event) //nolint:errcheck // Nothing to do if closing the event fails.
+
+	o := &syscall.Overlapped{HEvent: event}
+
+	c.mu.Lock()
+	if c.closed.Load() {
+		c.mu.Unlock()
+		return 0, net.ErrClosed
+	}
+	c.pending.Add(1)
+	defer c.pending.Done()
+
+	var n uint32
+	err = op(c.handle, p, &n, o)
+	c.mu.Unlock()
+
+	if errors.Is(err, syscall.ERROR_IO_PENDING) {
+		err = getOverlappedResult(c.handle, o, &n)
+	}
+	runtime.KeepAlive(o)
+
+	switch {
+	case err == nil, errors.Is(err, syscall.ERROR_MORE_DATA):
+		return int(n), nil
+	case errors.Is(err, syscall.ERROR_OPERATION_ABORTED) && c.closed.Load():
+		return int(n), net.ErrClosed
+	default:
+		return int(n), &net.OpError{Op: "io", Net: c.addr.Network(), Addr: c.addr, Err: err}
+	}
+}
+
+// Close closes the named pipe, aborting the pending reads and writes.
+func (c *pipeConn) Close() error {
+	c.mu.Lock()
+	if c.closed.Swap(true) {
+		c.mu.Unlock()
+		return net.ErrClosed
+	}
+
+	// no operation can be started anymore, so cancelling the started ones once is enough
+	_ = syscall.CancelIoEx(c.handle, nil)
+	c.mu.Unlock()
+
+	c.pending.Wait()
+
+	return syscall.CloseHandle(c.handle)
+}
+
+// LocalAddr returns the address of the named pipe.
+func (c *pipeConn) LocalAddr() net.Addr { return c.addr }
+
+// RemoteAddr returns the address of the named pipe.
+func (c *pipeConn) RemoteAddr() net.Addr { return c.addr }
+
+// SetDeadline is not supported, as for the files opened with the os package.
+func (c *pipeConn) SetDeadline(time.Time) error { return os.ErrNoDeadline }
+
+// SetReadDeadline is not supported, as for the files opened with the os package.
+func (c *pipeConn) SetReadDeadline(time.Time) error { return os.ErrNoDeadline }
+
+// SetWriteDeadline is not supported, as for the files opened with the os package.
+func (c *pipeConn) SetWriteDeadline(time.Time) error { return os.ErrNoDeadline }
+
+// createEvent creates a manual-reset event, signaling the completion of an overlapped operation.
+func createEvent() (syscall.Handle, error) {
+	h, _, err := procCreateEventW.Call(0, 1, 0, 0)
+	if h == 0 {
+		return 0, fmt.Errorf("create event: %w", err)
+	}
+	return syscall.Handle(h), nil
+}
+
+// getOverlappedResult waits for the completion of an overlapped operation, returning its error.
+func getOverlappedResult(h syscall.Handle, o *syscall.Overlapped, n *uint32) error {
+	ok, _, err := procGetOverlappedResult.Call(uintptr(h), uintptr(unsafe.Pointer(o)), uintptr(unsafe.Pointer(n)), 1)
+	if ok == 0 {
+		return err
+	}
+	return nil
+}
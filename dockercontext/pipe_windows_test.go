@@ -0,0 +1,129 @@
+//go:build windows
+
+package dockercontext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialPipe(t *testing.T) {
+	t.Run("concurrent-read-write", func(tt *testing.T) {
+		addr := fmt.Sprintf(`\\.\pipe\dockercontext-test-%d`, time.Now().UnixNano())
+		server := listenPipe(tt, addr)
+
+		// the server answers once it reads the request
+		go func() {
+			f := server()
+			defer f.Close()
+
+			buf := make([]byte, 4)
+			if _, err := io.ReadFull(f, buf); err != nil {
+				return
+			}
+			_, _ = f.Write([]byte("pong"))
+		}()
+
+		conn, err := dialPipe(context.Background(), addr, time.Second)
+		require.NoError(tt, err)
+		defer conn.Close()
+
+		// a read is pending while the request is written, as in the HTTP transport
+		read := make(chan string, 1)
+		go func() {
+			buf := make([]byte, 4)
+			n, _ := io.ReadFull(conn, buf)
+			read <- string(buf[:n])
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		written := make(chan error, 1)
+		go func() {
+			_, err := conn.Write([]byte("ping"))
+			written <- err
+		}()
+
+		select {
+		case err := <-written:
+			require.NoError(tt, err)
+		case <-time.After(5 * time.Second):
+			require.FailNow(tt, "write blocked by the pending read")
+		}
+
+		select {
+		case got := <-read:
+			require.Equal(tt, "pong", got)
+		case <-time.After(5 * time.Second):
+			require.FailNow(tt, "timeout waiting for the response")
+		}
+	})
+
+	t.Run("close-pending-read", func(tt *testing.T) {
+		addr := fmt.Sprintf(`\\.\pipe\dockercontext-test-%d`, time.Now().UnixNano())
+		server := listenPipe(tt, addr)
+
+		accepted := make(chan *os.File, 1)
+		go func() { accepted <- server() }()
+
+		conn, err := dialPipe(context.Background(), addr, time.Second)
+		require.NoError(tt, err)
+		defer func() { (<-accepted).Close() }()
+
+		read := make(chan error, 1)
+		go func() {
+			_, err := conn.Read(make([]byte, 1))
+			read <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		require.NoError(tt, conn.Close())
+
+		select {
+		case err := <-read:
+			require.Error(tt, err)
+		case <-time.After(5 * time.Second):
+			require.FailNow(tt, "close didn't abort the pending read")
+		}
+	})
+}
+
+// listenPipe creates a single instance named pipe at the given address, returning
+// a function waiting for a client to connect to it.
+func listenPipe(t *testing.T, addr string) func() *os.File {
+	t.Helper()
+
+	const (
+		pipeAccessDuplex   = 0x3
+		errPipeConnected   = syscall.Errno(535)
+		pipeBufferSize     = 4096
+		pipeMaxInstances   = 1
+		pipeTypeByteWait   = 0x0
+		pipeDefaultTimeout = 0
+	)
+
+	name, err := syscall.UTF16PtrFromString(addr)
+	require.NoError(t, err)
+
+	h, _, callErr := modkernel32.NewProc("CreateNamedPipeW").Call(
+		uintptr(unsafe.Pointer(name)), pipeAccessDuplex, pipeTypeByteWait, pipeMaxInstances,
+		pipeBufferSize, pipeBufferSize, pipeDefaultTimeout, 0,
+	)
+	require.NotEqual(t, uintptr(syscall.InvalidHandle), h, "create named pipe: %v", callErr)
+
+	return func() *os.File {
+		ok, _, err := modkernel32.NewProc("ConnectNamedPipe").Call(h, 0)
+		if ok == 0 && err != errPipeConnected { //nolint:errorlint // Call returns the syscall.Errno as is.
+			syscall.CloseHandle(syscall.Handle(h)) //nolint:errcheck // The test fails on the client side.
+			return nil
+		}
+		return os.NewFile(h, addr)
+	}
+}
@@ -0,0 +1,190 @@
+package dockercontext
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultProbeTimeout is the timeout to probe the Docker endpoint of each context
+const defaultProbeTimeout = 5 * time.Second
+
+// maxProbeBodySize is the maximum size of the responses read when probing
+const maxProbeBodySize = 1 << 20
+
+// ProbeOptions configures the probe of the Docker contexts.
+type ProbeOptions struct {
+	// Timeout is the time given to each context to answer the probe.
+	// It defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// ProbeResult is the outcome of probing the Docker endpoint of a context.
+type ProbeResult struct {
+	// ContextName is the name of the probed context.
+	ContextName string
+
+	// Host is the address of the Docker daemon of the context.
+	Host string
+
+	// Latency is the time taken by the ping request, including establishing the connection.
+	Latency time.Duration
+
+	// APIVersion is the maximum API version supported by the Docker daemon, e.g. "1.47".
+	APIVersion string
+
+	// ServerVersion is the version of the Docker daemon, e.g. "27.3.1".
+	ServerVersion string
+
+	// OS is the operating system of the Docker daemon, e.g. "linux".
+	OS string
+
+	// Arch is the architecture of the Docker daemon, e.g. "amd64".
+	Arch string
+
+	// Err is the reason the context couldn't be probed, if any.
+	// When set, the fields above may be partially filled.
+	Err error
+}
+
+// Probe is like [ProbeWithOptions], with the default options.
+func Probe(ctx context.Context) ([]ProbeResult, error) {
+	return ProbeWithOptions(ctx, ProbeOptions{})
+}
+
+// ProbeWithOptions checks if the Docker daemons of all the contexts returned by [List]
+// are reachable, probing them concurrently with the "GET /_ping" and "GET /version"
+// requests of the Docker API, over the transport of each endpoint.
+//
+// The results are in the same order as the contexts. The errors probing a context are
+// reported in its result, so the returned error is only set if the contexts can't be listed.
+func ProbeWithOptions(ctx context.Context, opts ProbeOptions) ([]ProbeResult, error) {
+	contexts, err := List()
+	if err != nil {
+		return nil, fmt.Errorf("list contexts: %w", err)
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultProbeTimeout
+	}
+
+	results := make([]ProbeResult, len(contexts))
+	var wg sync.WaitGroup
+	for i, c := range contexts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			results[i] = probeContext(ctx, c, opts.Timeout)
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// probeContext probes the Docker endpoint of the given context.
+func probeContext(ctx context.Context, c Context, timeout time.Duration) ProbeResult {
+	result := ProbeResult{ContextName: c.Name}
+
+	ep, err := ResolveDockerEndpoint(c.Name)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Host = ep.Host
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result.Err = probeEndpoint(ctx, ep, &result)
+
+	return result
+}
+
+// probeEndpoint fills the result with the responses of the ping and version
+// requests to the Docker endpoint.
+func probeEndpoint(ctx context.Context, ep DockerEndpoint, result *ProbeResult) error {
+	base, err := ep.URL()
+	if err != nil {
+		return err
+	}
+
+	transport, err := ep.Transport()
+	if err != nil {
+		return err
+	}
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{Transport: transport}
+
+	start := time.Now()
+	ping, err := probeGet(ctx, client, base.String()+"/_ping")
+	if err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	result.Latency = time.Since(start)
+	result.APIVersion = ping.header.Get("Api-Version")
+	result.OS = ping.header.Get("Ostype")
+
+	resp, err := probeGet(ctx, client, base.String()+"/version")
+	if err != nil {
+		return fmt.Errorf("version: %w", err)
+	}
+
+	var version struct {
+		Version    string `json:"Version"`
+		APIVersion string `json:"ApiVersion"`
+		OS         string `json:"Os"`
+		Arch       string `json:"Arch"`
+	}
+	if err := json.Unmarshal(resp.body, &version); err != nil {
+		return fmt.Errorf("decode version: %w", err)
+	}
+
+	result.ServerVersion = version.Version
+	result.Arch = version.Arch
+	if version.APIVersion != "" {
+		result.APIVersion = version.APIVersion
+	}
+	if version.OS != "" {
+		result.OS = version.OS
+	}
+
+	return nil
+}
+
+// probeResponse is a response read when probing
+type probeResponse struct {
+	header http.Header
+	body   []byte
+}
+
+// probeGet sends a GET request to the given URL, failing if the status is not 200 OK.
+func probeGet(ctx context.Context, client *http.Client, url string) (probeResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return probeResponse{}, fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return probeResponse{}, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodySize))
+	if err != nil {
+		return probeResponse{}, fmt.Errorf("read body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return probeResponse{}, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+
+	return probeResponse{header: resp.Header, body: body}, nil
+}
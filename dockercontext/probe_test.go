@@ -0,0 +1,130 @@
+package dockercontext
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not supported on Windows")
+	}
+
+	setupDockerContexts(t, 1, 1)
+
+	// the default context is alive
+	setupOverrides(t, "unix://"+serveDaemon(t, "default.sock", versionHandler(t, "1.47", "linux", "amd64", 0)), "")
+
+	require.NoError(t, Create(Context{
+		Name:      "builder-arm",
+		Endpoints: map[string]Endpoint{"docker": {Host: "unix://" + serveDaemon(t, "arm.sock", versionHandler(t, "1.45", "linux", "arm64", 0))}},
+	}))
+	require.NoError(t, Create(Context{
+		Name:      "builder-slow",
+		Endpoints: map[string]Endpoint{"docker": {Host: "unix://" + serveDaemon(t, "slow.sock", versionHandler(t, "1.47", "linux", "amd64", time.Second))}},
+	}))
+	require.NoError(t, Create(Context{
+		Name:      "builder-down",
+		Endpoints: map[string]Endpoint{"docker": {Host: "unix://" + filepath.Join(t.TempDir(), "down.sock")}},
+	}))
+
+	results, err := ProbeWithOptions(context.Background(), ProbeOptions{Timeout: 200 * time.Millisecond})
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.ContextName)
+	}
+	require.Equal(t, []string{DefaultContextName, "builder-arm", "builder-down", "builder-slow", "context1", "context2"}, names)
+
+	t.Run("alive", func(tt *testing.T) {
+		for _, result := range results[:2] {
+			require.NoError(tt, result.Err)
+			require.Positive(tt, result.Latency)
+			require.Equal(tt, "linux", result.OS)
+			require.Equal(tt, "27.3.1", result.ServerVersion)
+		}
+
+		require.Equal(tt, "1.47", results[0].APIVersion)
+		require.Equal(tt, "amd64", results[0].Arch)
+		require.Equal(tt, "1.45", results[1].APIVersion)
+		require.Equal(tt, "arm64", results[1].Arch)
+	})
+
+	t.Run("down", func(tt *testing.T) {
+		require.ErrorContains(tt, results[2].Err, "ping")
+		require.Contains(tt, results[2].Host, "down.sock")
+	})
+
+	t.Run("timeout", func(tt *testing.T) {
+		require.ErrorIs(tt, results[3].Err, context.DeadlineExceeded)
+	})
+
+	t.Run("unreachable", func(tt *testing.T) {
+		require.Error(tt, results[4].Err)
+		require.Equal(tt, "tcp://127.0.0.1:1", results[4].Host)
+	})
+
+	t.Run("host-not-set", func(tt *testing.T) {
+		require.ErrorIs(tt, results[5].Err, ErrDockerHostNotSet)
+	})
+
+	t.Run("canceled", func(tt *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results, err := Probe(ctx)
+		require.NoError(tt, err)
+		require.ErrorIs(tt, results[0].Err, context.Canceled)
+	})
+}
+
+// serveDaemon serves the given handler on a unix socket with the given name,
+// until the test ends. It returns the path of the socket.
+func serveDaemon(t *testing.T, name string, handler http.Handler) string {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), name)
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	return socket
+}
+
+// versionHandler returns a handler answering the Docker API ping and version
+// endpoints, after the given delay.
+func versionHandler(t *testing.T, apiVersion, os, arch string, delay time.Duration) http.Handler {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /_ping", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Api-Version", apiVersion)
+		w.Header().Set("Ostype", os)
+		_, _ = io.WriteString(w, "OK")
+	})
+	mux.HandleFunc("GET /version", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"Version":"27.3.1","ApiVersion":"`+apiVersion+`","Os":"`+os+`","Arch":"`+arch+`"}`)
+	})
+
+	return mux
+}
@@ -0,0 +1,140 @@
+package dockercontext
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseProperties parses a Java properties file, like ~/.testcontainers.properties,
+// into a map of keys and values. It supports the syntax used in practice:
+//
+//   - Blank lines, and comment lines starting with '#' or '!', are ignored.
+//   - Keys and values are separated by the first unescaped '=', ':' or whitespace.
+//   - A line ending with an odd number of backslashes continues in the next line.
+//   - The escapes \t, \n, \r, \f and \uXXXX are decoded, and any other escaped
+//     character is kept as is, e.g. "\=" or "\\".
+//
+// If a key is repeated, the last value wins.
+func ParseProperties(r io.Reader) (map[string]string, error) {
+	props := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	var logical strings.Builder
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if logical.Len() == 0 && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		if continues(line) {
+			logical.WriteString(line[:len(line)-1])
+			continue
+		}
+		logical.WriteString(line)
+
+		key, value, err := splitProperty(logical.String())
+		if err != nil {
+			return nil, err
+		}
+		props[key] = value
+		logical.Reset()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read properties: %w", err)
+	}
+
+	if logical.Len() > 0 {
+		key, value, err := splitProperty(logical.String())
+		if err != nil {
+			return nil, err
+		}
+		props[key] = value
+	}
+
+	return props, nil
+}
+
+// continues reports whether the line continues in the next one,
+// which happens when it ends with an odd number of backslashes.
+func continues(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+	return n%2 == 1
+}
+
+// splitProperty splits a logical line into its unescaped key and value.
+func splitProperty(line string) (string, string, error) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+
+	key, err := unescapeProperty(line[:end])
+	if err != nil {
+		return "", "", err
+	}
+
+	// the separator may be surrounded by whitespace, and only one '=' or ':' is part of it
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	value, err := unescapeProperty(rest)
+	if err != nil {
+		return "", "", err
+	}
+
+	return key, value, nil
+}
+
+// unescapeProperty decodes the escapes of a key or value of a properties file.
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("invalid unicode escape in %q", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape in %q: %w", s, err)
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,69 @@
+package dockercontext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProperties(t *testing.T) {
+	t.Run("success", func(tt *testing.T) {
+		props, err := ParseProperties(strings.NewReader(`
+# comment
+! another comment
+docker.host=tcp://127.0.0.1:2376
+docker.tls.verify = 1
+docker.cert.path: /certs
+tc.host tcp://127.0.0.1:5000
+empty=
+`))
+		require.NoError(tt, err)
+		require.Equal(tt, map[string]string{
+			"docker.host":       "tcp://127.0.0.1:2376",
+			"docker.tls.verify": "1",
+			"docker.cert.path":  "/certs",
+			"tc.host":           "tcp://127.0.0.1:5000",
+			"empty":             "",
+		}, props)
+	})
+
+	t.Run("escapes", func(tt *testing.T) {
+		props, err := ParseProperties(strings.NewReader(`key\=with\:separators=value\=with\\backslash
+tab=a\tb
+unicode=caf\u00e9
+windows.path=C:\\Users\\docker`))
+		require.NoError(tt, err)
+		require.Equal(tt, map[string]string{
+			"key=with:separators": `value=with\backslash`,
+			"tab":                 "a\tb",
+			"unicode":             "café",
+			"windows.path":        `C:\Users\docker`,
+		}, props)
+	})
+
+	t.Run("continuation", func(tt *testing.T) {
+		props, err := ParseProperties(strings.NewReader(`docker.host=tcp://\
+    127.0.0.1:\
+    2376
+escaped.backslash=value\\
+next=value`))
+		require.NoError(tt, err)
+		require.Equal(tt, map[string]string{
+			"docker.host":       "tcp://127.0.0.1:2376",
+			"escaped.backslash": `value\`,
+			"next":              "value",
+		}, props)
+	})
+
+	t.Run("last-wins", func(tt *testing.T) {
+		props, err := ParseProperties(strings.NewReader("key=first\nkey=second"))
+		require.NoError(tt, err)
+		require.Equal(tt, map[string]string{"key": "second"}, props)
+	})
+
+	t.Run("invalid-unicode", func(tt *testing.T) {
+		_, err := ParseProperties(strings.NewReader(`key=\u00zz`))
+		require.ErrorContains(tt, err, "invalid unicode escape")
+	})
+}
@@ -0,0 +1,247 @@
+package dockercontext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvSSHOpts is the name of the environment variable holding extra flags for the
+// ssh command used to connect to Docker daemons with an "ssh://" host, e.g. "-o ConnectTimeout=5".
+const EnvSSHOpts = "DOCKER_SSH_OPTS"
+
+// sshSocketPathRegEx is the format of the socket paths of "ssh://" hosts. The path is passed
+// to the remote shell, which ssh runs the command with, so shell metacharacters are rejected.
+//
+//nolint:gochecknoglobals // Compiled once.
+var sshSocketPathRegEx = regexp.MustCompile(`^/[A-Za-z0-9_./+@:,=-]*$`)
+
+// SSHOptions configures the connection to a Docker daemon with an "ssh://" host.
+type SSHOptions struct {
+	// IdentityFile is the private key file used to authenticate, passed with "-i".
+	IdentityFile string
+
+	// Flags are extra flags for the ssh command. If nil, they are read from the
+	// DOCKER_SSH_OPTS environment variable, split on whitespace.
+	Flags []string
+}
+
+// sshFlags returns the extra flags for the ssh command.
+func (o SSHOptions) sshFlags() []string {
+	if o.Flags != nil {
+		return o.Flags
+	}
+	return strings.Fields(os.Getenv(EnvSSHOpts))
+}
+
+// DialSSH connects to the Docker daemon of an "ssh://[user@]host[:port][/socket]" host,
+// running "ssh -- host docker system dial-stdio" and exposing its standard input
+// and output as a [net.Conn]. The ssh command is looked up in the PATH.
+//
+// When the host has a path, it's used as the unix socket of the remote Docker daemon.
+func DialSSH(ctx context.Context, host string, opts SSHOptions) (net.Conn, error) {
+	args, err := sshArgs(host, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return dialCommand(ctx, "ssh", args...)
+}
+
+// sshArgs returns the arguments of the ssh command connecting to the given host.
+func sshArgs(host string, opts SSHOptions) ([]string, error) {
+	u, err := parseSSHURL(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh host %q: %w", host, err)
+	}
+
+	// the flags are cloned, as appending to them would write into the caller's slice
+	args := slices.Clone(opts.sshFlags())
+	if opts.IdentityFile != "" {
+		args = append(args, "-i", opts.IdentityFile)
+	}
+	if user := u.User.Username(); user != "" {
+		args = append(args, "-l", user)
+	}
+	if port := u.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+
+	args = append(args, "--", u.Hostname(), "docker")
+	if u.Path != "" {
+		args = append(args, "--host", "unix://"+u.Path)
+	}
+
+	return append(args, "system", "dial-stdio"), nil
+}
+
+// parseSSHURL parses and validates a "ssh://[user@]host[:port][/socket]" host.
+func parseSSHURL(host string) (*url.URL, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "ssh" {
+		return nil, errors.New("scheme must be ssh")
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("no host specified")
+	}
+	if _, ok := u.User.Password(); ok {
+		return nil, errors.New("plain-text password is not supported")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, errors.New("extra parts are not supported")
+	}
+	if u.Path != "" && !sshSocketPathRegEx.MatchString(u.Path) {
+		return nil, fmt.Errorf("unsupported characters in socket path %q", u.Path)
+	}
+
+	return u, nil
+}
+
+// dialCommand starts the given command, returning a connection to its standard input and output.
+// The context only bounds the start of the command, which lives until the connection is closed.
+func dialCommand(ctx context.Context, name string, args ...string) (net.Conn, error) {
+	cmd := exec.Command(name, args...) //nolint:gosec // The arguments are built from a validated host.
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("stdout pipe: %w", err)
+	}
+
+	c := &commandConn{cmd: cmd, stdin: stdin, stdout: stdout}
+	cmd.Stderr = &c.stderr
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", name, err)
+	}
+
+	return c, nil
+}
+
+// commandConn is a [net.Conn] over the standard input and output of a command.
+type commandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	stderr lockedBuffer
+
+	waitOnce sync.Once
+	waitErr  error
+}
+
+// Read reads from the standard output of the command. When the command exits
+// with a failure, its standard error is included in the error.
+func (c *commandConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if errors.Is(err, io.EOF) && n == 0 {
+		if waitErr := c.wait(); waitErr != nil {
+			return 0, c.withStderr(fmt.Errorf("command exited: %w", waitErr))
+		}
+	}
+	return n, err
+}
+
+// Write writes to the standard input of the command.
+func (c *commandConn) Write(p []byte) (int, error) {
+	n, err := c.stdin.Write(p)
+	if err != nil {
+		return n, c.withStderr(err)
+	}
+	return n, nil
+}
+
+// CloseWrite closes the standard input of the command, signaling the end of the request.
+func (c *commandConn) CloseWrite() error {
+	return c.stdin.Close()
+}
+
+// Close closes the connection, killing the command if it's still running.
+func (c *commandConn) Close() error {
+	_ = c.stdin.Close()
+	_ = c.cmd.Process.Kill()
+	_ = c.wait()
+	return nil
+}
+
+// wait waits for the command to exit, and for its standard error to be captured.
+func (c *commandConn) wait() error {
+	c.waitOnce.Do(func() {
+		c.waitErr = c.cmd.Wait()
+	})
+	return c.waitErr
+}
+
+// LocalAddr returns a placeholder address, as the connection has no network address.
+func (c *commandConn) LocalAddr() net.Addr { return commandAddr{} }
+
+// RemoteAddr returns a placeholder address, as the connection has no network address.
+func (c *commandConn) RemoteAddr() net.Addr { return commandAddr{} }
+
+// SetDeadline is a no-op, as deadlines are not supported on command pipes.
+func (c *commandConn) SetDeadline(_ time.Time) error { return nil }
+
+// SetReadDeadline is a no-op, as deadlines are not supported on command pipes.
+func (c *commandConn) SetReadDeadline(_ time.Time) error { return nil }
+
+// SetWriteDeadline is a no-op, as deadlines are not supported on command pipes.
+func (c *commandConn) SetWriteDeadline(_ time.Time) error { return nil }
+
+// withStderr includes the standard error of the command in the given error.
+func (c *commandConn) withStderr(err error) error {
+	if stderr := c.stderr.String(); stderr != "" {
+		return fmt.Errorf("command stderr: %s: %w", stderr, err)
+	}
+	return err
+}
+
+// commandAddr is the placeholder address of a command connection.
+type commandAddr struct{}
+
+// Network returns the name of the network.
+func (commandAddr) Network() string { return "command" }
+
+// String returns the placeholder address.
+func (commandAddr) String() string { return "command" }
+
+// lockedBuffer is a buffer safe for concurrent use, capturing the standard error of a command.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write appends p to the buffer.
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// String returns the trimmed contents of the buffer.
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.TrimSpace(b.buf.String())
+}
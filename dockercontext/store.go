@@ -0,0 +1,118 @@
+package dockercontext
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+	"github.com/mdelapenya/docker-sdk-go/dockercontext/internal"
+)
+
+// ErrContextExists is the error returned when creating a Docker context that already exists
+var ErrContextExists = internal.ErrContextExists
+
+// errNotCurrent is used to skip the configuration update when the
+// removed context is not the current one.
+var errNotCurrent = errors.New("not the current context")
+
+// removeContext is a variable that can be used to mock internal.Remove in tests.
+var removeContext = internal.Remove //nolint:gochecknoglobals // This is used to mock removal failures in tests.
+
+// ValidateName checks the name of a context against the rules of the docker CLI:
+// it must start with a letter or a digit, and only contain letters, digits,
+// and the "_", ".", "+" and "-" characters. The default context name is reserved.
+func ValidateName(name string) error {
+	return internal.ValidateName(name, DefaultContextName)
+}
+
+// Create stores a new Docker context, like "docker context create".
+// Its metadata is written into the "contexts/meta/<sha256(name)>" directory
+// of the Docker configuration directory, exactly as the docker CLI does.
+//
+// It returns [ErrContextExists] if a context with the same name already exists.
+func Create(ctx Context) error {
+	if err := ValidateName(ctx.Name); err != nil {
+		return err
+	}
+
+	metaRoot, err := metaRoot()
+	if err != nil {
+		return fmt.Errorf("meta root: %w", err)
+	}
+
+	return internal.Create(ctx, metaRoot)
+}
+
+// Update replaces the metadata of an existing Docker context, like "docker context update".
+// To change a single value, use [Inspect] to read the context first, so its other
+// metadata, including fields and endpoints unknown to this package, are kept.
+//
+// It returns [ErrContextNotFound] if there is no such context.
+func Update(ctx Context) error {
+	if ctx.Name == DefaultContextName {
+		return errors.New("the default context cannot be updated")
+	}
+
+	metaRoot, err := metaRoot()
+	if err != nil {
+		return fmt.Errorf("meta root: %w", err)
+	}
+
+	return internal.Update(ctx, metaRoot)
+}
+
+// RemoveOptions configures the removal of a Docker context.
+type RemoveOptions struct {
+	// Force removes the context even if it's the current one,
+	// resetting the current context in the configuration file.
+	Force bool
+}
+
+// Remove deletes a Docker context and its TLS material, like "docker context rm".
+// It refuses to remove the current context, selected with DOCKER_CONTEXT or in the
+// configuration file, unless forced.
+//
+// It returns [ErrContextNotFound] if there is no such context.
+func Remove(name string, opts RemoveOptions) error {
+	if name == DefaultContextName {
+		return errors.New("the default context cannot be removed")
+	}
+
+	metaRoot, err := metaRoot()
+	if err != nil {
+		return fmt.Errorf("meta root: %w", err)
+	}
+
+	if _, err := internal.Inspect(name, metaRoot); err != nil {
+		return err
+	}
+
+	// the in-use check, the removal and the reset of the current context are done
+	// under the lock of the config file, so a concurrent switch can't be lost
+	err = dockerconfig.Update(func(cfg *dockerconfig.Config) error {
+		// the current context is resolved as in Current, including DOCKER_HOST and DOCKER_CONTEXT
+		current := getContextFromEnv()
+		if current == "" {
+			current = cfg.CurrentContext
+		}
+		if current == name && !opts.Force {
+			return fmt.Errorf("context %q is in use, force the removal to remove it", name)
+		}
+
+		// remove the metadata first, so a failure leaves the current context untouched
+		if err := removeContext(name, metaRoot); err != nil {
+			return err
+		}
+
+		if cfg.CurrentContext != name {
+			return errNotCurrent
+		}
+		cfg.CurrentContext = ""
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNotCurrent) {
+		return err
+	}
+
+	return nil
+}
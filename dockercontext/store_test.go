@@ -0,0 +1,179 @@
+package dockercontext
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+	"github.com/mdelapenya/docker-sdk-go/dockercontext/internal"
+)
+
+func TestCreate(t *testing.T) {
+	t.Run("success", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		err := Create(Context{
+			Name:        "remote",
+			Description: "remote host",
+			Endpoints: map[string]Endpoint{
+				"docker": {Host: "tcp://1.2.3.4:2375"},
+			},
+		})
+		require.NoError(tt, err)
+
+		ctx, err := Inspect("remote")
+		require.NoError(tt, err)
+		require.Equal(tt, "remote host", ctx.Description)
+		require.Equal(tt, "tcp://1.2.3.4:2375", ctx.Endpoints["docker"].Host)
+
+		metaDir, err := metaRoot()
+		require.NoError(tt, err)
+		require.Equal(tt, filepath.Join(metaDir, "b71199ebd070b36beab7317920c2c2f1d777df8d05e5527d8458fda57cb17a7a"), ctx.Storage.MetadataPath)
+	})
+
+	t.Run("invalid-name", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		require.Error(tt, Create(Context{Name: DefaultContextName}))
+		require.Error(tt, Create(Context{Name: "../remote"}))
+	})
+
+	t.Run("already-exists", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		err := Create(Context{Name: "context1"})
+		require.ErrorIs(tt, err, ErrContextExists)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("success", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		ctx, err := Inspect("context1")
+		require.NoError(tt, err)
+
+		ctx.Description = "updated"
+		require.NoError(tt, Update(ctx))
+
+		ctx, err = Inspect("context1")
+		require.NoError(tt, err)
+		require.Equal(tt, "updated", ctx.Description)
+		require.Equal(tt, "tcp://127.0.0.1:1", ctx.Endpoints["docker"].Host)
+	})
+
+	t.Run("default", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		require.Error(tt, Update(Context{Name: DefaultContextName}))
+	})
+
+	t.Run("not-found", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		err := Update(Context{Name: "context-not-found"})
+		require.ErrorIs(tt, err, ErrContextNotFound)
+	})
+}
+
+func TestRemove(t *testing.T) {
+	t.Run("success", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "")
+
+		require.NoError(tt, Remove("context2", RemoveOptions{}))
+
+		_, err := Inspect("context2")
+		require.ErrorIs(tt, err, ErrContextNotFound)
+
+		current, err := Current()
+		require.NoError(tt, err)
+		require.Equal(tt, "context1", current)
+	})
+
+	t.Run("current", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "")
+
+		err := Remove("context1", RemoveOptions{})
+		require.ErrorContains(tt, err, "is in use")
+
+		_, err = Inspect("context1")
+		require.NoError(tt, err)
+	})
+
+	t.Run("current/env", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "context2")
+
+		err := Remove("context2", RemoveOptions{})
+		require.ErrorContains(tt, err, "is in use")
+
+		_, err = Inspect("context2")
+		require.NoError(tt, err)
+	})
+
+	t.Run("current/env/force", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "context2")
+
+		require.NoError(tt, Remove("context2", RemoveOptions{Force: true}))
+
+		_, err := Inspect("context2")
+		require.ErrorIs(tt, err, ErrContextNotFound)
+
+		// the current context of the config file is another one, so it's kept
+		cfg, err := dockerconfig.Load()
+		require.NoError(tt, err)
+		require.Equal(tt, "context1", cfg.CurrentContext)
+	})
+
+	t.Run("current/force", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "")
+
+		require.NoError(tt, Remove("context1", RemoveOptions{Force: true}))
+
+		_, err := Inspect("context1")
+		require.ErrorIs(tt, err, ErrContextNotFound)
+
+		cfg, err := dockerconfig.Load()
+		require.NoError(tt, err)
+		require.Empty(tt, cfg.CurrentContext)
+	})
+
+	t.Run("current/force/remove-error", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "")
+
+		errRemove := errors.New("remove metadata: permission denied")
+		removeContext = func(string, string) error { return errRemove }
+		tt.Cleanup(func() { removeContext = internal.Remove })
+
+		err := Remove("context1", RemoveOptions{Force: true})
+		require.ErrorIs(tt, err, errRemove)
+
+		_, err = Inspect("context1")
+		require.NoError(tt, err)
+
+		current, err := Current()
+		require.NoError(tt, err)
+		require.Equal(tt, "context1", current)
+	})
+
+	t.Run("default", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		require.Error(tt, Remove(DefaultContextName, RemoveOptions{Force: true}))
+	})
+
+	t.Run("not-found", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		err := Remove("context-not-found", RemoveOptions{})
+		require.ErrorIs(tt, err, ErrContextNotFound)
+	})
+}
@@ -0,0 +1,203 @@
+package dockercontext
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+)
+
+const (
+	// TestcontainersPropertiesFile is the name of the Testcontainers properties file,
+	// in the user's home directory.
+	TestcontainersPropertiesFile = ".testcontainers.properties"
+
+	// EnvTestcontainersDockerHost is the name of the environment variable overriding
+	// the "docker.host" property of the Testcontainers properties file.
+	EnvTestcontainersDockerHost = "TESTCONTAINERS_DOCKER_HOST"
+
+	// EnvTestcontainersTLSVerify is the name of the environment variable overriding
+	// the "docker.tls.verify" property of the Testcontainers properties file.
+	EnvTestcontainersTLSVerify = "TESTCONTAINERS_DOCKER_TLS_VERIFY"
+
+	// EnvTestcontainersCertPath is the name of the environment variable overriding
+	// the "docker.cert.path" property of the Testcontainers properties file.
+	EnvTestcontainersCertPath = "TESTCONTAINERS_DOCKER_CERT_PATH"
+
+	// EnvTestcontainersHost is the name of the environment variable overriding
+	// the "tc.host" property of the Testcontainers properties file.
+	EnvTestcontainersHost = "TESTCONTAINERS_TC_HOST"
+)
+
+// TestcontainersConfig is the Docker configuration of Testcontainers, read from
+// the Testcontainers properties file and the TESTCONTAINERS_* environment variables.
+type TestcontainersConfig struct {
+	// DockerHost is the Docker host, from the "docker.host" property.
+	DockerHost string
+
+	// TLSVerify enables TLS to connect to DockerHost, from the "docker.tls.verify" property.
+	TLSVerify bool
+
+	// CertPath is the directory of the ca.pem, cert.pem and key.pem files used
+	// when TLSVerify is enabled, from the "docker.cert.path" property.
+	CertPath string
+
+	// TestcontainersHost is the host of the Testcontainers Cloud or Desktop daemon,
+	// from the "tc.host" property. It takes precedence over DockerHost.
+	TestcontainersHost string
+}
+
+// LoadTestcontainersConfig reads the Testcontainers properties file from the user's home
+// directory, if it exists, and applies the overrides of the TESTCONTAINERS_* environment
+// variables, which take precedence over the file.
+func LoadTestcontainersConfig() (TestcontainersConfig, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return TestcontainersConfig{}, fmt.Errorf("home dir: %w", err)
+	}
+
+	return LoadTestcontainersConfigFromFilepath(filepath.Join(home, TestcontainersPropertiesFile))
+}
+
+// LoadTestcontainersConfigFromFilepath is like [LoadTestcontainersConfig],
+// reading the properties file from the given path.
+func LoadTestcontainersConfigFromFilepath(path string) (TestcontainersConfig, error) {
+	props := make(map[string]string)
+
+	f, err := os.Open(path)
+	switch {
+	case err == nil:
+		defer f.Close()
+
+		if props, err = ParseProperties(f); err != nil {
+			return TestcontainersConfig{}, fmt.Errorf("parse %q: %w", path, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return TestcontainersConfig{}, fmt.Errorf("open properties: %w", err)
+	}
+
+	for key, env := range map[string]string{
+		"docker.host":       EnvTestcontainersDockerHost,
+		"docker.tls.verify": EnvTestcontainersTLSVerify,
+		"docker.cert.path":  EnvTestcontainersCertPath,
+		"tc.host":           EnvTestcontainersHost,
+	} {
+		if value := os.Getenv(env); value != "" {
+			props[key] = value
+		}
+	}
+
+	cfg := TestcontainersConfig{
+		DockerHost:         strings.TrimSpace(props["docker.host"]),
+		CertPath:           strings.TrimSpace(props["docker.cert.path"]),
+		TestcontainersHost: strings.TrimSpace(props["tc.host"]),
+	}
+
+	if verify := strings.TrimSpace(props["docker.tls.verify"]); verify != "" {
+		if cfg.TLSVerify, err = strconv.ParseBool(verify); err != nil {
+			return TestcontainersConfig{}, fmt.Errorf("invalid docker.tls.verify %q: %w", verify, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// DockerEndpoint resolves the Docker endpoint configured for Testcontainers:
+// TestcontainersHost if set, or DockerHost otherwise, with the TLS configuration
+// loaded from CertPath, or the Docker configuration directory if not set,
+// when TLSVerify is enabled.
+//
+// It returns [ErrHostNotDiscovered] if no host is configured.
+func (c TestcontainersConfig) DockerEndpoint() (DockerEndpoint, error) {
+	if c.TestcontainersHost != "" {
+		return DockerEndpoint{Host: c.TestcontainersHost}, nil
+	}
+
+	if c.DockerHost == "" {
+		return DockerEndpoint{}, fmt.Errorf("no host in testcontainers config: %w", ErrHostNotDiscovered)
+	}
+
+	ep := DockerEndpoint{Host: c.DockerHost}
+	if !c.TLSVerify {
+		return ep, nil
+	}
+
+	certPath := c.CertPath
+	if certPath == "" {
+		dir, err := dockerconfig.Dir()
+		if err != nil {
+			return DockerEndpoint{}, fmt.Errorf("docker config dir: %w", err)
+		}
+		certPath = dir
+	}
+
+	tlsConfig, err := tlsConfigFromDir(certPath)
+	if err != nil {
+		return DockerEndpoint{}, fmt.Errorf("tls config: %w", err)
+	}
+	ep.TLSConfig = tlsConfig
+
+	return ep, nil
+}
+
+// TestcontainersHostStrategy discovers the Testcontainers Cloud or Desktop daemon,
+// from the "tc.host" property read with [LoadTestcontainersConfig]. Testcontainers
+// checks it before any other source, see [TestcontainersStrategies].
+func TestcontainersHostStrategy() HostStrategy {
+	return HostStrategy{
+		Name: "testcontainers-host",
+		Discover: func() (DockerEndpoint, error) {
+			cfg, err := LoadTestcontainersConfig()
+			if err != nil {
+				return DockerEndpoint{}, fmt.Errorf("load testcontainers config: %w", err)
+			}
+
+			if cfg.TestcontainersHost == "" {
+				return DockerEndpoint{}, fmt.Errorf("no tc.host in testcontainers config: %w", ErrHostNotDiscovered)
+			}
+
+			return DockerEndpoint{Host: cfg.TestcontainersHost}, nil
+		},
+	}
+}
+
+// TestcontainersStrategy discovers the Docker host of the "docker.host" property, with
+// its TLS configuration, read with [LoadTestcontainersConfig]. Testcontainers checks it
+// after DOCKER_HOST, the Docker context and the default socket, see [TestcontainersStrategies].
+func TestcontainersStrategy() HostStrategy {
+	return HostStrategy{
+		Name: "testcontainers",
+		Discover: func() (DockerEndpoint, error) {
+			cfg, err := LoadTestcontainersConfig()
+			if err != nil {
+				return DockerEndpoint{}, fmt.Errorf("load testcontainers config: %w", err)
+			}
+
+			cfg.TestcontainersHost = ""
+			return cfg.DockerEndpoint()
+		},
+	}
+}
+
+// TestcontainersStrategies returns [DefaultStrategies] in the order Testcontainers resolves
+// the Docker host, for tools to connect to the same Docker daemon as the tests do: the
+// "tc.host" property first, then DOCKER_HOST, the Docker context and the default socket,
+// then the "docker.host" property, and the remaining default strategies.
+//
+//	DiscoverDockerHost(TestcontainersStrategies()...)
+func TestcontainersStrategies() []HostStrategy {
+	strategies := []HostStrategy{TestcontainersHostStrategy()}
+	for _, s := range DefaultStrategies() {
+		strategies = append(strategies, s)
+		if s.Name == "default-socket" {
+			strategies = append(strategies, TestcontainersStrategy())
+		}
+	}
+
+	return strategies
+}
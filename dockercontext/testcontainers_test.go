@@ -0,0 +1,152 @@
+package dockercontext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTestcontainersConfig(t *testing.T) {
+	t.Run("file", func(tt *testing.T) {
+		setupTestcontainersProperties(tt, "docker.host=tcp://127.0.0.1:2376\ndocker.tls.verify=true\ndocker.cert.path=/certs\ntc.host=tcp://127.0.0.1:5000\n")
+
+		cfg, err := LoadTestcontainersConfig()
+		require.NoError(tt, err)
+		require.Equal(tt, TestcontainersConfig{
+			DockerHost:         "tcp://127.0.0.1:2376",
+			TLSVerify:          true,
+			CertPath:           "/certs",
+			TestcontainersHost: "tcp://127.0.0.1:5000",
+		}, cfg)
+	})
+
+	t.Run("env-overrides", func(tt *testing.T) {
+		setupTestcontainersProperties(tt, "docker.host=tcp://127.0.0.1:2376\ndocker.tls.verify=1\n")
+		tt.Setenv(EnvTestcontainersDockerHost, "tcp://127.0.0.1:2375")
+		tt.Setenv(EnvTestcontainersTLSVerify, "0")
+
+		cfg, err := LoadTestcontainersConfig()
+		require.NoError(tt, err)
+		require.Equal(tt, TestcontainersConfig{DockerHost: "tcp://127.0.0.1:2375"}, cfg)
+	})
+
+	t.Run("no-file", func(tt *testing.T) {
+		setupTestcontainersProperties(tt, "")
+		tt.Setenv(EnvTestcontainersHost, "tcp://127.0.0.1:5000")
+
+		cfg, err := LoadTestcontainersConfig()
+		require.NoError(tt, err)
+		require.Equal(tt, TestcontainersConfig{TestcontainersHost: "tcp://127.0.0.1:5000"}, cfg)
+	})
+
+	t.Run("invalid-tls-verify", func(tt *testing.T) {
+		setupTestcontainersProperties(tt, "docker.tls.verify=maybe\n")
+
+		_, err := LoadTestcontainersConfig()
+		require.ErrorContains(tt, err, `invalid docker.tls.verify "maybe"`)
+	})
+}
+
+func TestTestcontainersStrategy(t *testing.T) {
+	t.Run("tc-host", func(tt *testing.T) {
+		setupTestcontainersProperties(tt, "docker.host=tcp://127.0.0.1:2376\ntc.host=tcp://127.0.0.1:5000\n")
+
+		discovery, err := DiscoverDockerHost(TestcontainersHostStrategy())
+		require.NoError(tt, err)
+		require.Equal(tt, Discovery{
+			Strategy: "testcontainers-host",
+			Endpoint: DockerEndpoint{Host: "tcp://127.0.0.1:5000"},
+		}, discovery)
+
+		discovery, err = DiscoverDockerHost(TestcontainersStrategy())
+		require.NoError(tt, err)
+		require.Equal(tt, Discovery{
+			Strategy: "testcontainers",
+			Endpoint: DockerEndpoint{Host: "tcp://127.0.0.1:2376"},
+		}, discovery)
+	})
+
+	t.Run("tc-host/not-configured", func(tt *testing.T) {
+		setupTestcontainersProperties(tt, "docker.host=tcp://127.0.0.1:2376\n")
+
+		_, err := TestcontainersHostStrategy().Discover()
+		require.ErrorIs(tt, err, ErrHostNotDiscovered)
+	})
+
+	t.Run("docker-host/tls", func(tt *testing.T) {
+		certPath := tt.TempDir()
+		writeCertificates(tt, certPath)
+		setupTestcontainersProperties(tt, "docker.host=tcp://127.0.0.1:2376\ndocker.tls.verify=1\n")
+		tt.Setenv(EnvTestcontainersCertPath, certPath)
+
+		discovery, err := DiscoverDockerHost(TestcontainersStrategy())
+		require.NoError(tt, err)
+		require.Equal(tt, "tcp://127.0.0.1:2376", discovery.Endpoint.Host)
+		require.NotNil(tt, discovery.Endpoint.TLSConfig)
+		require.Len(tt, discovery.Endpoint.TLSConfig.Certificates, 1)
+	})
+
+	t.Run("precedence", func(tt *testing.T) {
+		setupTestcontainersProperties(tt, "docker.host=tcp://127.0.0.1:2376\n")
+		setupOverrides(tt, "tcp://127.0.0.1:1234", "")
+
+		discovery, err := DiscoverDockerHost(TestcontainersStrategies()...)
+		require.NoError(tt, err)
+		require.Equal(tt, "env", discovery.Strategy)
+		require.Equal(tt, "tcp://127.0.0.1:1234", discovery.Endpoint.Host)
+	})
+
+	t.Run("precedence/tc-host", func(tt *testing.T) {
+		setupTestcontainersProperties(tt, "docker.host=tcp://127.0.0.1:2376\ntc.host=tcp://127.0.0.1:5000\n")
+		setupOverrides(tt, "tcp://127.0.0.1:1234", "")
+
+		discovery, err := DiscoverDockerHost(TestcontainersStrategies()...)
+		require.NoError(tt, err)
+		require.Equal(tt, "testcontainers-host", discovery.Strategy)
+		require.Equal(tt, "tcp://127.0.0.1:5000", discovery.Endpoint.Host)
+	})
+
+	t.Run("order", func(tt *testing.T) {
+		var names []string
+		for _, s := range TestcontainersStrategies() {
+			names = append(names, s.Name)
+		}
+
+		require.Equal(tt, []string{
+			"testcontainers-host", "env", "context", "default-socket", "testcontainers",
+			"rootless", "docker-desktop", "colima", "rancher-desktop", "podman",
+		}, names)
+	})
+
+	t.Run("not-configured", func(tt *testing.T) {
+		setupTestcontainersProperties(tt, "ryuk.disabled=true\n")
+		setupOverrides(tt, "tcp://127.0.0.1:1234", "")
+
+		discovery, err := DiscoverDockerHost(TestcontainersStrategy(), EnvStrategy())
+		require.NoError(tt, err)
+		require.Equal(tt, "env", discovery.Strategy)
+	})
+}
+
+// setupTestcontainersProperties sets up an empty home directory with the given
+// Testcontainers properties file, if not empty, clearing the environment variables
+// overriding it.
+func setupTestcontainersProperties(t *testing.T, content string) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home) // Windows support
+
+	t.Setenv(EnvTestcontainersDockerHost, "")
+	t.Setenv(EnvTestcontainersTLSVerify, "")
+	t.Setenv(EnvTestcontainersCertPath, "")
+	t.Setenv(EnvTestcontainersHost, "")
+
+	if content != "" {
+		err := os.WriteFile(filepath.Join(home, TestcontainersPropertiesFile), []byte(content), 0o600)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,52 @@
+package dockercontext
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/mdelapenya/docker-sdk-go/dockercontext/internal"
+)
+
+// TLSConfig returns the TLS configuration to connect to the Docker endpoint of the
+// context with the given name. It loads the CA, certificate and key stored by
+// "docker context create --docker host=...,ca=...,cert=...,key=..." under the
+// "contexts/tls/<sha256(name)>/docker" directory, honouring the endpoint's
+// SkipTLSVerify setting.
+//
+// It returns nil if the endpoint has no TLS material and doesn't skip the TLS
+// verification, meaning the endpoint doesn't use TLS.
+//
+// For the default context, the TLS material is loaded from the DOCKER_CERT_PATH
+// directory when DOCKER_TLS_VERIFY or DOCKER_TLS is set, as the docker CLI does.
+func TLSConfig(name string) (*tls.Config, error) {
+	ctx, err := Inspect(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return endpointTLSConfig(ctx, dockerEndpoint)
+}
+
+// endpointTLSConfig returns the TLS configuration of the given endpoint of a context.
+func endpointTLSConfig(ctx Context, endpointName string) (*tls.Config, error) {
+	ep, ok := ctx.Endpoints[endpointName]
+	if !ok {
+		return nil, fmt.Errorf("context %q: endpoint %q not found", ctx.Name, endpointName)
+	}
+
+	if ctx.Name == DefaultContextName {
+		return defaultTLSConfig()
+	}
+
+	metaRoot, err := metaRoot()
+	if err != nil {
+		return nil, fmt.Errorf("meta root: %w", err)
+	}
+
+	data, err := internal.LoadTLSData(ctx.Name, endpointName, metaRoot)
+	if err != nil {
+		return nil, fmt.Errorf("load tls data: %w", err)
+	}
+
+	return internal.TLSConfig(data, ep.SkipTLSVerify)
+}
@@ -0,0 +1,64 @@
+package dockercontext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfig(t *testing.T) {
+	t.Run("no-tls", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		cfg, err := TLSConfig("context1")
+		require.NoError(tt, err)
+		require.Nil(tt, cfg)
+	})
+
+	t.Run("skip-tls-verify", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		require.NoError(tt, Create(Context{
+			Name: "remote",
+			Endpoints: map[string]Endpoint{
+				"docker": {Host: "tcp://1.2.3.4:2376", SkipTLSVerify: true},
+			},
+		}))
+
+		cfg, err := TLSConfig("remote")
+		require.NoError(tt, err)
+		require.True(tt, cfg.InsecureSkipVerify)
+	})
+
+	t.Run("invalid-tls-material", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		ctx, err := Inspect("context1")
+		require.NoError(tt, err)
+
+		dir := filepath.Join(ctx.Storage.TLSPath, "docker")
+		tempMkdirAll(tt, dir)
+		require.NoError(tt, os.WriteFile(filepath.Join(dir, "ca.pem"), []byte("invalid"), 0o600))
+
+		_, err = TLSConfig("context1")
+		require.ErrorContains(tt, err, "invalid CA certificate")
+	})
+
+	t.Run("not-found", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		_, err := TLSConfig("context-not-found")
+		require.ErrorIs(tt, err, ErrContextNotFound)
+	})
+
+	t.Run("no-docker-endpoint", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 1)
+
+		require.NoError(tt, Create(Context{Name: "kube-only", Endpoints: map[string]Endpoint{"kubernetes": {}}}))
+
+		_, err := TLSConfig("kube-only")
+		require.ErrorContains(tt, err, `endpoint "docker" not found`)
+	})
+}
@@ -0,0 +1,99 @@
+package dockercontext
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DummyHost is the placeholder host used in the URLs of the requests to a Docker
+// daemon listening on a socket, which has no host name. It's the same one used
+// by the Docker client, and resolves to the loopback address per RFC 6761.
+const DummyHost = "api.moby.localhost"
+
+// defaultDialTimeout is the timeout to establish a connection to the Docker daemon.
+const defaultDialTimeout = 30 * time.Second
+
+// URL returns the base URL of the requests to the endpoint: "http://api.moby.localhost"
+// for sockets, and "http://host:port" or "https://host:port" for TCP, depending on
+// the TLS configuration, followed by the base path of the host, if any.
+// The API version prefix, e.g. "/v1.47", is not included.
+func (e DockerEndpoint) URL() (*url.URL, error) {
+	h, err := ParseHost(e.TLSConfig != nil, e.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &url.URL{Scheme: "http", Host: DummyHost}
+	if h.Proto == "tcp" {
+		u.Host = h.Addr
+		u.Path = h.BasePath
+		if e.TLSConfig != nil {
+			u.Scheme = "https"
+		}
+	}
+
+	return u, nil
+}
+
+// HTTPClient returns an [http.Client] connecting to the Docker daemon of the endpoint,
+// with the right dialer for its protocol: "unix://" sockets, "tcp://" with or without TLS,
+// "ssh://" through [DialSSH], and "npipe://" on Windows. Requests must be sent to the base URL returned by [DockerEndpoint.URL].
+func (e DockerEndpoint) HTTPClient() (*http.Client, error) {
+	transport, err := e.Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// Transport returns an [http.Transport] connecting to the Docker daemon of the endpoint.
+// See [DockerEndpoint.HTTPClient] for details.
+func (e DockerEndpoint) Transport() (*http.Transport, error) {
+	h, err := ParseHost(e.TLSConfig != nil, e.Host)
+	if err != nil {
+		return nil, err
+	}
+	proto, addr := h.Proto, h.Addr
+
+	transport := &http.Transport{
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	switch proto {
+	case "unix":
+		dialer := &net.Dialer{Timeout: defaultDialTimeout}
+		transport.DisableCompression = true
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, proto, addr)
+		}
+	case "npipe":
+		transport.DisableCompression = true
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialPipe(ctx, addr, defaultDialTimeout)
+		}
+	case "ssh":
+		host := e.Host
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return DialSSH(ctx, host, SSHOptions{})
+		}
+	case "tcp":
+		dialer := &net.Dialer{Timeout: defaultDialTimeout, KeepAlive: 30 * time.Second}
+		transport.Proxy = http.ProxyFromEnvironment
+		transport.DialContext = dialer.DialContext
+		if e.TLSConfig != nil {
+			transport.TLSClientConfig = e.TLSConfig.Clone()
+		}
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q in docker host %q", proto, e.Host)
+	}
+
+	return transport, nil
+}
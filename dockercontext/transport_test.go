@@ -0,0 +1,135 @@
+package dockercontext
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerEndpoint_URL(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint DockerEndpoint
+		want     string
+	}{
+		{name: "unix", endpoint: DockerEndpoint{Host: "unix:///var/run/docker.sock"}, want: "http://api.moby.localhost"},
+		{name: "ssh", endpoint: DockerEndpoint{Host: "ssh://me@example.com"}, want: "http://api.moby.localhost"},
+		{name: "npipe", endpoint: DockerEndpoint{Host: "npipe:////./pipe/docker_engine"}, want: "http://api.moby.localhost"},
+		{name: "tcp", endpoint: DockerEndpoint{Host: "tcp://1.2.3.4:2375"}, want: "http://1.2.3.4:2375"},
+		{name: "tcp/tls", endpoint: DockerEndpoint{Host: "tcp://1.2.3.4:2376", TLSConfig: &tls.Config{}}, want: "https://1.2.3.4:2376"},
+		{name: "tcp/base-path", endpoint: DockerEndpoint{Host: "tcp://1.2.3.4:2375/docker/"}, want: "http://1.2.3.4:2375/docker"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(tt *testing.T) {
+			u, err := tc.endpoint.URL()
+			require.NoError(tt, err)
+			require.Equal(tt, tc.want, u.String())
+		})
+	}
+
+	t.Run("invalid", func(tt *testing.T) {
+		_, err := DockerEndpoint{Host: "/var/run/docker.sock"}.URL()
+		require.ErrorContains(tt, err, "invalid docker host")
+	})
+}
+
+func TestDockerEndpoint_HTTPClient(t *testing.T) {
+	t.Run("unix", func(tt *testing.T) {
+		if runtime.GOOS == "windows" {
+			tt.Skip("unix sockets are not supported on Windows")
+		}
+
+		socket := filepath.Join(tt.TempDir(), "docker.sock")
+		l, err := net.Listen("unix", socket)
+		require.NoError(tt, err)
+
+		srv := httptest.NewUnstartedServer(pingHandler(tt))
+		srv.Listener = l
+		srv.Start()
+		tt.Cleanup(srv.Close)
+
+		requirePing(tt, DockerEndpoint{Host: "unix://" + socket})
+	})
+
+	t.Run("tcp", func(tt *testing.T) {
+		srv := httptest.NewServer(pingHandler(tt))
+		tt.Cleanup(srv.Close)
+
+		requirePing(tt, DockerEndpoint{Host: "tcp://" + srv.Listener.Addr().String()})
+	})
+
+	t.Run("tcp/tls", func(tt *testing.T) {
+		srv := httptest.NewTLSServer(pingHandler(tt))
+		tt.Cleanup(srv.Close)
+
+		pool := x509.NewCertPool()
+		pool.AddCert(srv.Certificate())
+
+		requirePing(tt, DockerEndpoint{
+			Host:      "tcp://" + srv.Listener.Addr().String(),
+			TLSConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		})
+	})
+
+	t.Run("npipe", func(tt *testing.T) {
+		if runtime.GOOS == "windows" {
+			tt.Skip("named pipes are supported on Windows")
+		}
+
+		client, err := DockerEndpoint{Host: "npipe:////./pipe/docker_engine"}.HTTPClient()
+		require.NoError(tt, err)
+
+		_, err = client.Get("http://" + DummyHost + "/_ping") //nolint:noctx // test request
+		require.ErrorContains(tt, err, "npipe protocol is only supported on Windows")
+	})
+
+	t.Run("unsupported-protocol", func(tt *testing.T) {
+		_, err := DockerEndpoint{Host: "udp://1.2.3.4:2375"}.HTTPClient()
+		require.ErrorContains(tt, err, `unsupported protocol "udp"`)
+	})
+}
+
+// pingHandler returns a handler answering the Docker API ping endpoint
+func pingHandler(t *testing.T) http.Handler {
+	t.Helper()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/_ping") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Api-Version", "1.47")
+		_, _ = io.WriteString(w, "OK")
+	})
+}
+
+// requirePing pings the Docker daemon of the endpoint, and verifies the response
+func requirePing(t *testing.T, ep DockerEndpoint) {
+	t.Helper()
+
+	client, err := ep.HTTPClient()
+	require.NoError(t, err)
+
+	u, err := ep.URL()
+	require.NoError(t, err)
+
+	resp, err := client.Get(u.String() + "/_ping") //nolint:noctx // test request
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "OK", string(body))
+	require.Equal(t, "1.47", resp.Header.Get("Api-Version"))
+}
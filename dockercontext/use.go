@@ -0,0 +1,60 @@
+package dockercontext
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+)
+
+// Use sets the Docker context with the given name as the current one, like
+// "docker context use". Using the default context clears the current context
+// of the configuration file. The configuration file is saved with [dockerconfig.Update],
+// keeping all its other keys intact.
+//
+// It returns [ErrContextNotFound] if there is no such context. The returned warnings
+// report the environment variables overriding the current context, if any,
+// as the docker CLI does.
+func Use(name string) ([]string, error) {
+	if name != DefaultContextName {
+		if _, err := Inspect(name); err != nil {
+			return nil, err
+		}
+	}
+
+	err := dockerconfig.Update(func(cfg *dockerconfig.Config) error {
+		if name == DefaultContextName {
+			cfg.CurrentContext = ""
+		} else {
+			cfg.CurrentContext = name
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update docker config: %w", err)
+	}
+
+	return overrideWarnings(name), nil
+}
+
+// overrideWarnings returns the warnings for the environment variables
+// overriding the current context in the configuration file.
+func overrideWarnings(name string) []string {
+	var warnings []string
+
+	if os.Getenv(EnvOverrideHost) != "" {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s environment variable overrides the active context. To use %q, unset the %s environment variable.",
+			EnvOverrideHost, name, EnvOverrideHost,
+		))
+	}
+
+	if ctxName := os.Getenv(EnvOverrideContext); ctxName != "" && ctxName != name {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s environment variable overrides the active context. To use %q, unset the %s environment variable.",
+			EnvOverrideContext, name, EnvOverrideContext,
+		))
+	}
+
+	return warnings
+}
@@ -0,0 +1,109 @@
+package dockercontext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mdelapenya/docker-sdk-go/dockerconfig"
+)
+
+func TestUse(t *testing.T) {
+	t.Run("success", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "")
+
+		warnings, err := Use("context2")
+		require.NoError(tt, err)
+		require.Empty(tt, warnings)
+
+		current, err := Current()
+		require.NoError(tt, err)
+		require.Equal(tt, "context2", current)
+	})
+
+	t.Run("keeps-other-keys", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "")
+
+		configPath, err := dockerconfig.Filepath()
+		require.NoError(tt, err)
+		require.NoError(tt, os.WriteFile(configPath, []byte(`{"currentContext":"context1","credsStore":"desktop","features":{"buildkit":"true"}}`), 0o600))
+
+		_, err = Use("context2")
+		require.NoError(tt, err)
+
+		data, err := os.ReadFile(configPath)
+		require.NoError(tt, err)
+		require.JSONEq(tt, `{"auths":{},"currentContext":"context2","credsStore":"desktop","features":{"buildkit":"true"}}`, string(data))
+	})
+
+	t.Run("default", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "")
+
+		_, err := Use(DefaultContextName)
+		require.NoError(tt, err)
+
+		cfg, err := dockerconfig.Load()
+		require.NoError(tt, err)
+		require.Empty(tt, cfg.CurrentContext)
+	})
+
+	t.Run("no-config-file", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "")
+
+		configDir, err := dockerconfig.Dir()
+		require.NoError(tt, err)
+		require.NoError(tt, os.Remove(filepath.Join(configDir, dockerconfig.FileName)))
+
+		_, err = Use("context2")
+		require.NoError(tt, err)
+
+		current, err := Current()
+		require.NoError(tt, err)
+		require.Equal(tt, "context2", current)
+	})
+
+	t.Run("not-found", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+
+		_, err := Use("context-not-found")
+		require.ErrorIs(tt, err, ErrContextNotFound)
+
+		cfg, err := dockerconfig.Load()
+		require.NoError(tt, err)
+		require.Equal(tt, "context1", cfg.CurrentContext)
+	})
+
+	t.Run("warnings", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "tcp://127.0.0.1:123", "context1")
+
+		warnings, err := Use("context2")
+		require.NoError(tt, err)
+		require.Len(tt, warnings, 2)
+		require.Contains(tt, warnings[0], "DOCKER_HOST environment variable overrides the active context")
+		require.Contains(tt, warnings[1], "DOCKER_CONTEXT environment variable overrides the active context")
+	})
+
+	t.Run("warnings/same-context", func(tt *testing.T) {
+		setupDockerContexts(tt, 1, 2)
+		setupOverrides(tt, "", "context2")
+
+		warnings, err := Use("context2")
+		require.NoError(tt, err)
+		require.Empty(tt, warnings)
+	})
+}
+
+// setupOverrides sets the environment variables overriding the current context
+func setupOverrides(t *testing.T, host, context string) {
+	t.Helper()
+
+	t.Setenv(EnvOverrideHost, host)
+	t.Setenv(EnvOverrideContext, context)
+}